@@ -9,6 +9,98 @@ const __dirname = dirname(__filename);
 
 let calculatePoint;
 let calculateMandelbrotSet;
+let calculateMandelbrotSetTyped;
+let calculateJuliaPoint;
+let calculateJuliaSet;
+let renderViewport;
+let calculateMultibrotPoint;
+let calculateBurningShipPoint;
+let calculateBurningShipSet;
+let calculateTricornPoint;
+let calculateTricornSet;
+let calculatePointWithMagnitude;
+let calculateOrbitTrapPoint;
+let calculateOrbit;
+let getMemoryBuffer;
+let renderToMemory;
+let setWorkerCount;
+let renderRGBA;
+let computeHistogram;
+let setHighPrecision;
+let renderPerturbation;
+let renderMarianiSilver;
+let renderViewportPass;
+let benchmarkIterations;
+let setStructuredResults;
+let calculateNewtonPoint;
+let calculateStripePoint;
+let accumulateBuddhabrot;
+let calculateNormalized;
+let isInSet;
+let renderTile;
+let setDefaults;
+let renderRGBASmooth;
+let calculateMandelbrotSetBuffer;
+let generateCoordinates;
+let calculateDistanceEstimate;
+let renderViewportBudget;
+let getConcurrency;
+let setFixedPoint;
+let setNeighborGuessing;
+let setBailoutShape;
+let renderSmoothFloat32;
+let renderExponentialMap;
+let calculateLinearEscapeTime;
+let createRenderer;
+let renderStep;
+let getBuffer;
+let destroyRenderer;
+let calculatePointWithPhase;
+let regionStats;
+let setSeriesApproximation;
+let calculateSeriesSkip;
+let calculateMandelbrotInterleaved;
+let checkPrecision;
+let setCheckInterval;
+let getCapabilities;
+let setColoringMode;
+let calculateLemniscateLevel;
+let cancelRender;
+let renderViewportState;
+let continueRender;
+let setJitter;
+let setSeed;
+let estimateArea;
+let calculateHalleyPoint;
+let calculatePotential;
+let computeGrid;
+let setInteriorValue;
+let setPeriodicityCheck;
+let renderFloatTexture;
+let calculateOrbitTrapLine;
+let calculateOrbitTrapCross;
+let setStrictLengths;
+let calculateLogistic;
+let setPalette;
+let recommendedIterations;
+let diffRegion;
+let renderRows;
+let juliaKeyframe;
+let allocBuffer;
+let freeBuffer;
+let setInclusiveEscape;
+let findNearbyPeriodicPoint;
+let setBounds;
+let renderBlend;
+let hashBuffer;
+let renderRGBAWithLUT;
+let encodeDeltas;
+let decodeDeltas;
+let traceExternalRay;
+let setMaxIterationsLimit;
+let computeScanline;
+let renderNormals;
+let wasmMemory;
 
 beforeAll(async () => {
   // Load the wasm_exec.js helper from Go
@@ -40,10 +132,102 @@ beforeAll(async () => {
   
   // Run the Go program (this registers the calculatePoint function)
   go.run(wasmModule.instance);
+  wasmMemory = wasmModule.instance.exports.mem;
   
   // Get the functions from global scope
   calculatePoint = global.calculatePoint;
   calculateMandelbrotSet = global.calculateMandelbrotSet;
+  calculateMandelbrotSetTyped = global.calculateMandelbrotSetTyped;
+  calculateJuliaPoint = global.calculateJuliaPoint;
+  calculateJuliaSet = global.calculateJuliaSet;
+  renderViewport = global.renderViewport;
+  calculateMultibrotPoint = global.calculateMultibrotPoint;
+  calculateBurningShipPoint = global.calculateBurningShipPoint;
+  calculateBurningShipSet = global.calculateBurningShipSet;
+  calculateTricornPoint = global.calculateTricornPoint;
+  calculateTricornSet = global.calculateTricornSet;
+  calculatePointWithMagnitude = global.calculatePointWithMagnitude;
+  calculateOrbitTrapPoint = global.calculateOrbitTrapPoint;
+  calculateOrbit = global.calculateOrbit;
+  getMemoryBuffer = global.getMemoryBuffer;
+  renderToMemory = global.renderToMemory;
+  setWorkerCount = global.setWorkerCount;
+  renderRGBA = global.renderRGBA;
+  computeHistogram = global.computeHistogram;
+  setHighPrecision = global.setHighPrecision;
+  renderPerturbation = global.renderPerturbation;
+  renderMarianiSilver = global.renderMarianiSilver;
+  renderViewportPass = global.renderViewportPass;
+  benchmarkIterations = global.benchmarkIterations;
+  setStructuredResults = global.setStructuredResults;
+  calculateNewtonPoint = global.calculateNewtonPoint;
+  calculateStripePoint = global.calculateStripePoint;
+  accumulateBuddhabrot = global.accumulateBuddhabrot;
+  calculateNormalized = global.calculateNormalized;
+  isInSet = global.isInSet;
+  renderTile = global.renderTile;
+  setDefaults = global.setDefaults;
+  renderRGBASmooth = global.renderRGBASmooth;
+  calculateMandelbrotSetBuffer = global.calculateMandelbrotSetBuffer;
+  generateCoordinates = global.generateCoordinates;
+  calculateDistanceEstimate = global.calculateDistanceEstimate;
+  renderViewportBudget = global.renderViewportBudget;
+  getConcurrency = global.getConcurrency;
+  setFixedPoint = global.setFixedPoint;
+  setNeighborGuessing = global.setNeighborGuessing;
+  setBailoutShape = global.setBailoutShape;
+  renderSmoothFloat32 = global.renderSmoothFloat32;
+  renderExponentialMap = global.renderExponentialMap;
+  calculateLinearEscapeTime = global.calculateLinearEscapeTime;
+  createRenderer = global.createRenderer;
+  renderStep = global.renderStep;
+  getBuffer = global.getBuffer;
+  destroyRenderer = global.destroyRenderer;
+  calculatePointWithPhase = global.calculatePointWithPhase;
+  regionStats = global.regionStats;
+  setSeriesApproximation = global.setSeriesApproximation;
+  calculateSeriesSkip = global.calculateSeriesSkip;
+  calculateMandelbrotInterleaved = global.calculateMandelbrotInterleaved;
+  checkPrecision = global.checkPrecision;
+  setCheckInterval = global.setCheckInterval;
+  getCapabilities = global.getCapabilities;
+  setColoringMode = global.setColoringMode;
+  calculateLemniscateLevel = global.calculateLemniscateLevel;
+  cancelRender = global.cancelRender;
+  renderViewportState = global.renderViewportState;
+  continueRender = global.continueRender;
+  setJitter = global.setJitter;
+  setSeed = global.setSeed;
+  estimateArea = global.estimateArea;
+  calculateHalleyPoint = global.calculateHalleyPoint;
+  calculatePotential = global.calculatePotential;
+  computeGrid = global.computeGrid;
+  setInteriorValue = global.setInteriorValue;
+  setPeriodicityCheck = global.setPeriodicityCheck;
+  renderFloatTexture = global.renderFloatTexture;
+  calculateOrbitTrapLine = global.calculateOrbitTrapLine;
+  calculateOrbitTrapCross = global.calculateOrbitTrapCross;
+  setStrictLengths = global.setStrictLengths;
+  calculateLogistic = global.calculateLogistic;
+  setPalette = global.setPalette;
+  recommendedIterations = global.recommendedIterations;
+  diffRegion = global.diffRegion;
+  renderRows = global.renderRows;
+  juliaKeyframe = global.juliaKeyframe;
+  allocBuffer = global.allocBuffer;
+  freeBuffer = global.freeBuffer;
+  setInclusiveEscape = global.setInclusiveEscape;
+  findNearbyPeriodicPoint = global.findNearbyPeriodicPoint;
+  setBounds = global.setBounds;
+  renderBlend = global.renderBlend;
+  hashBuffer = global.hashBuffer;
+  renderRGBAWithLUT = global.renderRGBAWithLUT;
+  encodeDeltas = global.encodeDeltas;
+  decodeDeltas = global.decodeDeltas;
+  traceExternalRay = global.traceExternalRay;
+  setMaxIterationsLimit = global.setMaxIterationsLimit;
+  computeScanline = global.computeScanline;
+  renderNormals = global.renderNormals;
 });
 
 describe('Go WebAssembly Mandelbrot Calculation - Property Tests', () => {
@@ -122,4 +306,3596 @@ describe('Go WebAssembly Mandelbrot Calculation - Property Tests', () => {
       { numRuns: 100 }
     );
   });
+
+  // Feature: mandelbrot-visualizer, Property 2a: Smooth iteration counts stay consistent with integer counts
+  test('Property 2a: Smooth iteration count agrees with integer count', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (real, imag, maxIterations, escapeRadius) => {
+          const iterations = calculatePoint(real, imag, maxIterations, escapeRadius);
+          const smooth = calculatePoint(real, imag, maxIterations, escapeRadius, true);
+
+          // smooth=false must keep the original integer behaviour
+          expect(calculatePoint(real, imag, maxIterations, escapeRadius, false)).toBe(iterations);
+
+          if (iterations === maxIterations) {
+            // Non-escaping points still report maxIterations
+            expect(smooth).toBe(maxIterations);
+          } else {
+            expect(Number.isFinite(smooth)).toBe(true);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4c: Julia batch calculation matches single-point Julia results
+  test('Property 4c: Julia batch calculation produces consistent results', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.double({ min: -2, max: 2, noNaN: true }), { minLength: 1, maxLength: 50 }), // real coords
+        fc.array(fc.double({ min: -2, max: 2, noNaN: true }), { minLength: 1, maxLength: 50 }), // imag coords
+        fc.double({ min: -1, max: 1, noNaN: true }),  // c real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // c imag
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (realCoords, imagCoords, cReal, cImag, maxIterations, escapeRadius) => {
+          const batchResults = calculateJuliaSet(realCoords, imagCoords, cReal, cImag, maxIterations, escapeRadius);
+
+          const length = Math.min(realCoords.length, imagCoords.length);
+          expect(batchResults.length).toBe(length);
+          for (let i = 0; i < length; i++) {
+            const single = calculateJuliaPoint(realCoords[i], imagCoords[i], cReal, cImag, maxIterations, escapeRadius);
+            expect(batchResults[i]).toBe(single);
+            expect(single).toBeLessThanOrEqual(maxIterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4d: Julia orbit from the origin matches the Mandelbrot point
+  test('Property 4d: Julia point starting at zero equals the Mandelbrot point for c', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // c real
+        fc.double({ min: -3, max: 3, noNaN: true }),  // c imag
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (cReal, cImag, maxIterations, escapeRadius) => {
+          expect(calculateJuliaPoint(0, 0, cReal, cImag, maxIterations, escapeRadius))
+            .toBe(calculatePoint(cReal, cImag, maxIterations, escapeRadius));
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4e: Typed-array batch matches the array batch
+  test('Property 4e: Typed-array batch calculation matches array batch calculation', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 100 }), // real coords
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 100 }), // imag coords
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (realCoords, imagCoords, maxIterations, escapeRadius) => {
+          const resultBuf = new Uint32Array(Math.max(realCoords.length, imagCoords.length));
+          const written = calculateMandelbrotSetTyped(
+            new Float64Array(realCoords),
+            new Float64Array(imagCoords),
+            resultBuf,
+            maxIterations,
+            escapeRadius
+          );
+
+          const expected = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius);
+          expect(written).toBe(expected.length);
+          for (let i = 0; i < written; i++) {
+            expect(resultBuf[i]).toBe(expected[i]);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4f: Viewport rendering matches per-pixel calculation
+  test('Property 4f: renderViewport matches calculatePoint for each pixel', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const resultBuf = new Uint32Array(width * height);
+          const written = renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf);
+          expect(written).toBe(width * height);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const real = centerReal + (x - width / 2) * scale;
+              const imag = centerImag - (y - height / 2) * scale;
+              expect(resultBuf[y * width + x]).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4g: Cardioid/bulb shortcut does not change results
+  test('Property 4g: Interior shortcut matches full iteration near the cardioid and bulb', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -1.3, max: 0.4, noNaN: true }), // real component
+        fc.double({ min: -0.7, max: 0.7, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 2, max: 10, noNaN: true }),  // escape_radius
+        (real, imag, maxIterations, escapeRadius) => {
+          // calculateJuliaPoint starting at zero iterates the same orbit without the shortcut
+          const expected = calculateJuliaPoint(0, 0, real, imag, maxIterations, escapeRadius);
+          expect(calculatePoint(real, imag, maxIterations, escapeRadius)).toBe(expected);
+          expect(calculateMandelbrotSet([real], [imag], maxIterations, escapeRadius)[0]).toBe(expected);
+        }
+      ),
+      { numRuns: 200 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2b: Multibrot power 2 matches the Mandelbrot set
+  test('Property 2b: Multibrot with power 2 is identical to calculatePoint', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (real, imag, maxIterations, escapeRadius) => {
+          expect(calculateMultibrotPoint(real, imag, 2, maxIterations, escapeRadius))
+            .toBe(calculatePoint(real, imag, maxIterations, escapeRadius));
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2c: Multibrot iteration count bounded by maximum
+  test('Property 2c: Multibrot iteration count is bounded and powers below 2 are rejected', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 3, max: 8 }),               // power
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        (real, imag, power, maxIterations) => {
+          const result = calculateMultibrotPoint(real, imag, power, maxIterations, 2.0);
+          expect(result).toBeLessThanOrEqual(maxIterations);
+          expect(result).toBeGreaterThanOrEqual(0);
+          expect(calculateMultibrotPoint(real, imag, 1, maxIterations, 2.0)).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4h: Burning Ship batch matches single-point results
+  test('Property 4h: Burning Ship batch calculation produces consistent results', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 50 }), // real coords
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 50 }), // imag coords
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (realCoords, imagCoords, maxIterations, escapeRadius) => {
+          const batchResults = calculateBurningShipSet(realCoords, imagCoords, maxIterations, escapeRadius);
+
+          const length = Math.min(realCoords.length, imagCoords.length);
+          expect(batchResults.length).toBe(length);
+          for (let i = 0; i < length; i++) {
+            const single = calculateBurningShipPoint(realCoords[i], imagCoords[i], maxIterations, escapeRadius);
+            expect(batchResults[i]).toBe(single);
+            expect(single).toBeLessThanOrEqual(maxIterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4i: Tricorn batch matches single-point results
+  test('Property 4i: Tricorn batch calculation produces consistent results', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 50 }), // real coords
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 50 }), // imag coords
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (realCoords, imagCoords, maxIterations, escapeRadius) => {
+          const batchResults = calculateTricornSet(realCoords, imagCoords, maxIterations, escapeRadius);
+
+          const length = Math.min(realCoords.length, imagCoords.length);
+          expect(batchResults.length).toBe(length);
+          for (let i = 0; i < length; i++) {
+            const single = calculateTricornPoint(realCoords[i], imagCoords[i], maxIterations, escapeRadius);
+            expect(batchResults[i]).toBe(single);
+            // The Tricorn is symmetric about the real axis
+            expect(calculateTricornPoint(realCoords[i], -imagCoords[i], maxIterations, escapeRadius)).toBe(single);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3a: Reported magnitude is consistent with escape
+  test('Property 3a: Magnitude at escape exceeds the escape radius', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (real, imag, maxIterations, escapeRadius) => {
+          const { iterations, magnitudeSquared } = calculatePointWithMagnitude(real, imag, maxIterations, escapeRadius);
+          expect(iterations).toBe(calculatePoint(real, imag, maxIterations, escapeRadius));
+
+          if (iterations < maxIterations) {
+            expect(magnitudeSquared).toBeGreaterThan(escapeRadius * escapeRadius);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4j: Rendering into linear memory matches renderViewport
+  test('Property 4j: renderToMemory writes the same values as renderViewport', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const pixels = width * height;
+          const offset = getMemoryBuffer(pixels * 4);
+          expect(renderToMemory(offset, width, height, centerReal, centerImag, scale, maxIterations, 2.0)).toBe(pixels);
+          const fromMemory = new Uint32Array(wasmMemory.buffer, offset, pixels).slice();
+
+          const expected = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected);
+          expect(Array.from(fromMemory)).toEqual(Array.from(expected));
+
+          // Offsets before the reserved region are rejected
+          expect(renderToMemory(offset - 4, width, height, centerReal, centerImag, scale, maxIterations, 2.0)).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Lengths beyond the wasm32 address space are rejected, not allocated
+    for (const bad of [0, -1, 2 ** 32 - 7, 1e15, Infinity, NaN]) {
+      expect(getMemoryBuffer(bad)).toHaveProperty('error');
+    }
+    expect(typeof getMemoryBuffer(16)).toBe('number');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4k: Worker count does not affect results
+  test('Property 4k: Batch and viewport results are independent of the worker count', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 100 }), // real coords
+        fc.array(fc.double({ min: -3, max: 3, noNaN: true }), { minLength: 1, maxLength: 100 }), // imag coords
+        fc.integer({ min: 2, max: 8 }),               // worker count
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (realCoords, imagCoords, workers, maxIterations) => {
+          try {
+            setWorkerCount(1);
+            const sequential = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, 2.0);
+            const sequentialView = new Uint32Array(realCoords.length * 4);
+            renderViewport(realCoords.length, 4, -0.5, 0, 0.01, maxIterations, 2.0, sequentialView);
+
+            expect(setWorkerCount(workers)).toBe(true);
+            const parallel = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, 2.0);
+            const parallelView = new Uint32Array(realCoords.length * 4);
+            renderViewport(realCoords.length, 4, -0.5, 0, 0.01, maxIterations, 2.0, parallelView);
+
+            expect(parallel).toEqual(sequential);
+            expect(Array.from(parallelView)).toEqual(Array.from(sequentialView));
+          } finally {
+            setWorkerCount(1);
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5a: RGBA renderer colors interior points black
+  test('Property 5a: renderRGBA writes opaque pixels with black interior', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const pixels = width * height;
+          const rgbaBuf = new Uint8ClampedArray(pixels * 4);
+          expect(renderRGBA(width, height, centerReal, centerImag, scale, maxIterations, 2.0, rgbaBuf)).toBe(pixels);
+
+          const iterations = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, iterations);
+
+          for (let i = 0; i < pixels; i++) {
+            expect(rgbaBuf[i * 4 + 3]).toBe(255);
+            if (iterations[i] === maxIterations) {
+              expect(rgbaBuf[i * 4] + rgbaBuf[i * 4 + 1] + rgbaBuf[i * 4 + 2]).toBe(0);
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5b: Histogram CDF is a normalized cumulative distribution
+  test('Property 5b: computeHistogram returns a non-decreasing CDF ignoring interior points', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        fc.array(fc.double({ min: 0, max: 1, noNaN: true }), { minLength: 1, maxLength: 200 }), // iteration fractions
+        (maxIterations, fractions) => {
+          const iterations = new Uint32Array(fractions.map((f) => Math.round(f * maxIterations)));
+          const cdf = computeHistogram(iterations, maxIterations);
+          expect(cdf.length).toBe(maxIterations);
+
+          for (let n = 1; n < cdf.length; n++) {
+            expect(cdf[n]).toBeGreaterThanOrEqual(cdf[n - 1]);
+          }
+
+          const escaped = iterations.filter((n) => n < maxIterations).length;
+          expect(cdf[maxIterations - 1]).toBe(escaped > 0 ? 1 : 0);
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5c: One sample per axis reproduces unsampled rendering
+  test('Property 5c: renderRGBA with samplesPerAxis 1 matches the default and supersampled pixels stay opaque', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),              // width
+        fc.integer({ min: 1, max: 12 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 2, max: 3 }),               // samples per axis
+        (width, height, centerReal, centerImag, scale, samplesPerAxis) => {
+          const pixels = width * height;
+          const plain = new Uint8ClampedArray(pixels * 4);
+          const single = new Uint8ClampedArray(pixels * 4);
+          const supersampled = new Uint8ClampedArray(pixels * 4);
+
+          renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, plain);
+          renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, single, 1);
+          expect(renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, supersampled, samplesPerAxis)).toBe(pixels);
+          expect(Array.from(single)).toEqual(Array.from(plain));
+
+          for (let i = 0; i < pixels; i++) {
+            expect(supersampled[i * 4 + 3]).toBe(255);
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // The factor is capped at 16 per axis
+    const rgbaBuf = new Uint8ClampedArray(4);
+    expect(renderRGBA(1, 1, 0, 0, 0.01, 10, 2, rgbaBuf, 16)).toBe(1);
+    for (const bad of [0, 17, 1e6, 2 ** 63, Infinity, NaN]) {
+      expect(renderRGBA(1, 1, 0, 0, 0.01, 10, 2, rgbaBuf, bad)).toHaveProperty('error');
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3b: Orbit trap distance is bounded by the first orbit point
+  test('Property 3b: Orbit trap reports the escape iteration and a minimum distance', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: -2, max: 2, noNaN: true }),  // trap real
+        fc.double({ min: -2, max: 2, noNaN: true }),  // trap imag
+        (real, imag, maxIterations, trapReal, trapImag) => {
+          const { iterations, distance } = calculateOrbitTrapPoint(real, imag, maxIterations, 2.0, trapReal, trapImag);
+          expect(iterations).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+
+          // z_1 = c is always part of the orbit, so the minimum can't exceed |c - trap|
+          expect(distance).toBeGreaterThanOrEqual(0);
+          expect(distance).toBeLessThanOrEqual(Math.hypot(real - trapReal, imag - trapImag) + 1e-12);
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3c: Orbit output is capped and ends on escape
+  test('Property 3c: calculateOrbit length is capped by maxPoints and the escape iteration', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.integer({ min: 1, max: 200 }),             // max_points
+        (real, imag, maxIterations, maxPoints) => {
+          const orbit = calculateOrbit(real, imag, maxIterations, 2.0, maxPoints);
+          const iterations = calculatePoint(real, imag, maxIterations, 2.0);
+
+          expect(orbit.length % 2).toBe(0);
+          expect(orbit.length / 2).toBe(Math.min(maxPoints, iterations));
+          expect(orbit[0]).toBe(real);
+          expect(orbit[1]).toBe(imag);
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4l: High precision resolves pixels float64 cannot
+  test('Property 4l: setHighPrecision distinguishes neighbouring pixels at deep zoom', () => {
+    const width = 16;
+    const height = 16;
+    // Around the Misiurewicz point c = i, 1e-19 per pixel is far below float64
+    // resolution, so the fast path sees a single point
+    const fast = new Uint32Array(width * height);
+    const precise = new Uint32Array(width * height);
+
+    try {
+      renderViewport(width, height, 0, 1, 1e-19, 1000, 2.0, fast);
+      expect(setHighPrecision(true)).toBe(true);
+      renderViewport(width, height, 0, 1, 1e-19, 1000, 2.0, precise);
+    } finally {
+      setHighPrecision(false);
+    }
+
+    expect(new Set(fast).size).toBe(1);
+    expect(new Set(precise).size).toBeGreaterThan(1);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4m: Perturbation matches direct rendering
+  test('Property 4m: renderPerturbation agrees with renderViewport and resolves deep zooms', () => {
+    const width = 32;
+    const height = 32;
+    const perturbed = new Uint32Array(width * height);
+    const glitches = new Uint8Array(width * height);
+    const direct = new Uint32Array(width * height);
+
+    expect(renderPerturbation(width, height, -0.5, 0, 0.05, 200, 2.0, perturbed, glitches)).toBe(width * height);
+    renderViewport(width, height, -0.5, 0, 0.05, 200, 2.0, direct);
+    for (let i = 0; i < width * height; i++) {
+      expect(glitches[i] === 0 || glitches[i] === 1).toBe(true);
+      if (glitches[i] === 0) {
+        expect(perturbed[i]).toBe(direct[i]);
+      }
+    }
+
+    // 1e-60 per pixel is far beyond double-double, but offsets from the center stay exact
+    renderPerturbation(width, height, 0, 1, 1e-60, 1000, 2.0, perturbed, glitches);
+    expect(new Set(perturbed).size).toBeGreaterThan(1);
+
+    // A glitch buffer of the wrong type is rejected
+    expect(renderPerturbation(width, height, 0, 1, 1e-60, 1000, 2.0, perturbed, direct)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4n: Mariani-Silver matches per-pixel rendering
+  test('Property 4n: renderMarianiSilver produces the same counts as renderViewport', () => {
+    const views = [
+      [96, 64, -0.5, 0, 0.04, 100],      // whole set, large uniform bands
+      [64, 64, -1.25, 0, 0.002, 500],    // period-4 bulb, interior not covered by the shortcut
+      [37, 23, -0.75, 0.1, 0.001, 300]   // odd sizes near the boundary
+    ];
+
+    for (const [width, height, centerReal, centerImag, scale, maxIterations] of views) {
+      const subdivided = new Uint32Array(width * height);
+      const direct = new Uint32Array(width * height);
+
+      expect(renderMarianiSilver(width, height, centerReal, centerImag, scale, maxIterations, 2.0, subdivided)).toBe(width * height);
+      renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, direct);
+      expect(Array.from(subdivided)).toEqual(Array.from(direct));
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4o: Progressive passes compute each pixel once
+  test('Property 4o: renderViewportPass passes 8, 4, 2, 1 reproduce renderViewport', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 40 }),                 // width
+        fc.integer({ min: 1, max: 40 }),                 // height
+        fc.double({ min: -1.5, max: 0.5, noNaN: true }), // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),     // center imag
+        (width, height, centerReal, centerImag) => {
+          const progressive = new Uint32Array(width * height);
+          const direct = new Uint32Array(width * height);
+          const gridSize = (stride) => Math.ceil(width / stride) * Math.ceil(height / stride);
+
+          let total = 0;
+          let previous = 0;
+          for (const stride of [8, 4, 2, 1]) {
+            const computed = renderViewportPass(width, height, centerReal, centerImag, 0.02, 100, 2.0, stride, 0, 0, progressive);
+            expect(computed).toBe(gridSize(stride) - previous);
+            previous = gridSize(stride);
+            total += computed;
+          }
+
+          renderViewport(width, height, centerReal, centerImag, 0.02, 100, 2.0, direct);
+          expect(total).toBe(width * height);
+          expect(Array.from(progressive)).toEqual(Array.from(direct));
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 6a: Benchmark totals are deterministic
+  test('Property 6a: benchmarkIterations repeats the same iteration total', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 0, max: 2000 }),  // point count
+        fc.integer({ min: 1, max: 200 }),   // max_iterations
+        (count, maxIterations) => {
+          const first = benchmarkIterations(count, maxIterations, 2.0);
+          const second = benchmarkIterations(count, maxIterations, 2.0);
+
+          expect(first.iterations).toBe(second.iterations);
+          expect(first.iterations).toBeGreaterThanOrEqual(0);
+          expect(first.iterations).toBeLessThanOrEqual(count * maxIterations);
+          expect(first.nanoseconds).toBeGreaterThanOrEqual(0);
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Counts beyond one block of 65536 points continue the same point sequence
+    const count = 65536 + 100;
+    let state = 0x9E3779B97F4A7C15n;
+    const next = () => {
+      state ^= state >> 12n;
+      state = (state ^ (state << 25n)) & 0xffffffffffffffffn;
+      state ^= state >> 27n;
+      return Number(((state * 0x2545F4914F6CDD1Dn) & 0xffffffffffffffffn) >> 11n) / 2 ** 53;
+    };
+    const realCoords = new Float64Array(count);
+    const imagCoords = new Float64Array(count);
+    for (let i = 0; i < count; i++) {
+      realCoords[i] = -2.0 + 2.5 * next();
+      imagCoords[i] = -1.25 + 2.5 * next();
+    }
+    const expected = calculateMandelbrotSet(realCoords, imagCoords, 20, 2.0).reduce((sum, n) => sum + n, 0);
+    expect(benchmarkIterations(count, 20, 2.0).iterations).toBe(expected);
+
+    // The interior value changes what is reported, not how much is iterated
+    const plain = benchmarkIterations(20000, 200, 2.0).iterations;
+    try {
+      for (const value of [0, 1, 200, 0xFFFFFFFF]) {
+        expect(setInteriorValue(value)).toBe(true);
+        expect(benchmarkIterations(20000, 200, 2.0).iterations).toBe(plain);
+        setPeriodicityCheck(true);
+        expect(benchmarkIterations(20000, 200, 2.0).iterations).toBe(plain);
+        setPeriodicityCheck(false);
+      }
+      // The setting is still in effect afterwards
+      expect(calculatePoint(-0.1, 0, 100, 2.0)).toBe(0xFFFFFFFF);
+    } finally {
+      setInteriorValue(null);
+      setPeriodicityCheck(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2d: Structured results agree with plain counts
+  test('Property 2d: setStructuredResults returns {escaped, iterations, smooth} consistent with calculatePoint', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        (real, imag, maxIterations) => {
+          const plain = calculatePoint(real, imag, maxIterations, 2.0);
+          let result;
+          try {
+            expect(setStructuredResults(true)).toBe(true);
+            result = calculatePoint(real, imag, maxIterations, 2.0);
+          } finally {
+            setStructuredResults(false);
+          }
+
+          expect(result.iterations).toBe(plain);
+          if (result.escaped) {
+            expect(Number.isFinite(result.smooth)).toBe(true);
+            if (plain < maxIterations) {
+              expect(result.smooth).toBe(calculatePoint(real, imag, maxIterations, 2.0, true));
+            }
+          } else {
+            expect(result.iterations).toBe(maxIterations);
+            expect(result.smooth).toBe(maxIterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // c = 2 escapes on exactly the last of two iterations, which a plain count can't show
+    try {
+      setStructuredResults(true);
+      expect(calculatePoint(2, 0, 2, 2.0).escaped).toBe(true);
+      expect(calculatePoint(-0.5, 0, 2, 2.0).escaped).toBe(false);
+      expect(calculateJuliaPoint(0, 0, 2, 0, 2, 2.0).escaped).toBe(true);
+      expect(calculateTricornPoint(0, 0, 100, 2.0).escaped).toBe(false);
+    } finally {
+      setStructuredResults(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 7a: Invalid arguments return descriptive errors
+  test('Property 7a: invalid arguments return {error} naming the function and argument', () => {
+    const cases = [
+      [() => calculatePoint(0, 0, 100), /^calculatePoint: expected 2, 4, 5, 6, 7, 8 or 9 arguments, got 3$/],
+      [() => calculatePoint('0', 0, 100, 2.0), /^calculatePoint: real must be a number, got string$/],
+      [() => calculatePoint(0, 0, 0.5, 2.0), /maxIterations must be a positive integer/],
+      [() => calculatePoint(0, 0, 100, -1), /escapeRadius must be greater than 0/],
+      [() => calculateMandelbrotSet([], [], 100, 2.0), /realCoords must not be empty/],
+      [() => calculateMandelbrotSet('abc', [0], 100, 2.0), /realCoords must be an array/],
+      [() => renderViewport(4, 4, 0, 0, 0.1, 100, 2.0, new Uint32Array(8)), /resultBuf must hold at least 16 elements, got 8/],
+      [() => renderViewport(4, 4, 0, 0, 0.1, 100, 2.0, new Float64Array(16)), /resultBuf must be a Uint32Array/],
+      [() => setWorkerCount(0), /^setWorkerCount: n must be a positive integer/],
+      // Oversized viewports are rejected before anything is allocated
+      [() => renderViewport(4294967297, 4294967295, -0.5, 0, 0.01, 10, 2, new Uint32Array(4)), /^renderViewport: width\*height must be at most 268435456 pixels/],
+      [() => renderViewport(Infinity, 1, -0.5, 0, 0.01, 10, 2, new Uint32Array(4)), /width\*height must be at most/],
+      [() => computeGrid(16385, 16384, -0.5, 0, 0.01, 10, 2), /width\*height must be at most/],
+      [() => renderTile(0, 0, 0, 1e6, 10, 2, new Uint32Array(4)), /tileSize\*tileSize must be at most/]
+    ];
+
+    for (const [call, message] of cases) {
+      const result = call();
+      expect(result).toHaveProperty('error');
+      expect(result.error).toMatch(message);
+    }
+
+    // The happy path keeps its plain return values
+    expect(calculatePoint(0, 0, 100, 2.0)).toBe(100);
+    expect(renderViewport(4, 4, 0, 0, 0.1, 100, 2.0, new Uint32Array(16))).toBe(16);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4p: Column-major output is the transpose of row-major output
+  test('Property 4p: columnMajor stores pixel (x, y) at x*height + y', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        (width, height, centerReal, centerImag, scale) => {
+          const pixels = width * height;
+          const rowMajor = new Uint32Array(pixels);
+          const columnMajor = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, 200, 2.0, rowMajor);
+          expect(renderViewport(width, height, centerReal, centerImag, scale, 200, 2.0, columnMajor, true)).toBe(pixels);
+
+          const offset = getMemoryBuffer(pixels * 4);
+          expect(renderToMemory(offset, width, height, centerReal, centerImag, scale, 200, 2.0, true)).toBe(pixels);
+          const fromMemory = new Uint32Array(wasmMemory.buffer, offset, pixels).slice();
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              expect(columnMajor[x * height + y]).toBe(rowMajor[y * width + x]);
+              expect(fromMemory[x * height + y]).toBe(rowMajor[y * width + x]);
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 8a: Newton's method reports a root it actually reached
+  test('Property 8a: calculateNewtonPoint converges to one of the three cube roots of unity', () => {
+    const roots = [[1, 0], [-0.5, Math.sqrt(3) / 2], [-0.5, -Math.sqrt(3) / 2]];
+
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        (real, imag, maxIterations) => {
+          const { iterations, root } = calculateNewtonPoint(real, imag, maxIterations, 1e-6);
+
+          expect(iterations).toBeLessThanOrEqual(maxIterations);
+          if (root === -1) {
+            expect(iterations).toBe(maxIterations);
+          } else {
+            expect([0, 1, 2]).toContain(root);
+            expect(iterations).toBeLessThan(maxIterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Starting on a root converges immediately; near a root converges to it
+    roots.forEach(([real, imag], index) => {
+      const onRoot = calculateNewtonPoint(real, imag, 50, 1e-6);
+      expect(onRoot.iterations).toBe(0);
+      expect(onRoot.root).toBe(index);
+      expect(calculateNewtonPoint(real * 1.1, imag * 1.1, 50, 1e-6).root).toBe(index);
+    });
+
+    // z = 0 has no Newton step
+    const atZero = calculateNewtonPoint(0, 0, 50, 1e-6);
+    expect(atZero.iterations).toBe(50);
+    expect(atZero.root).toBe(-1);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4q: Narrow iteration buffers hold clamped counts
+  test('Property 4q: bytesPerPixel 1 and 2 write counts clamped to the element range', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),              // width
+        fc.integer({ min: 1, max: 12 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.integer({ min: 1, max: 70000 }),           // max_iterations
+        (width, height, centerReal, centerImag, maxIterations) => {
+          const pixels = width * height;
+          const full = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, 0.05, maxIterations, 2.0, full);
+
+          for (const [bytesPerPixel, ArrayType, maxValue] of [[1, Uint8Array, 255], [2, Uint16Array, 65535]]) {
+            const narrow = new ArrayType(pixels);
+            expect(renderViewport(width, height, centerReal, centerImag, 0.05, maxIterations, 2.0, narrow, false, bytesPerPixel)).toBe(pixels);
+
+            const offset = getMemoryBuffer(pixels * bytesPerPixel);
+            expect(renderToMemory(offset, width, height, centerReal, centerImag, 0.05, maxIterations, 2.0, false, bytesPerPixel)).toBe(pixels);
+            const fromMemory = new ArrayType(wasmMemory.buffer, offset, pixels).slice();
+
+            for (let i = 0; i < pixels; i++) {
+              expect(narrow[i]).toBe(Math.min(full[i], maxValue));
+              expect(fromMemory[i]).toBe(Math.min(full[i], maxValue));
+            }
+          }
+
+          // The buffer type must match the element size
+          expect(renderViewport(width, height, centerReal, centerImag, 0.05, maxIterations, 2.0, full, false, 2)).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 30 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3d: Stripe average stays in [0, 1]
+  test('Property 3d: calculateStripePoint returns a unit stripe average and matching counts', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        fc.double({ min: 0, max: 20, noNaN: true }),  // stripe density
+        (real, imag, maxIterations, stripeDensity) => {
+          const { iterations, smooth, stripe } = calculateStripePoint(real, imag, maxIterations, 2.0, stripeDensity);
+
+          expect(iterations).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+          expect(smooth).toBe(calculatePoint(real, imag, maxIterations, 2.0, true));
+          expect(stripe).toBeGreaterThanOrEqual(0);
+          expect(stripe).toBeLessThanOrEqual(1);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // With no stripes every term is 0.5 + 0.5*sin(0)
+    expect(calculateStripePoint(-0.75, 0.1, 100, 2.0, 0).stripe).toBe(0.5);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2e: A custom z0 shifts only the starting value
+  test('Property 2e: calculatePoint with z0 matches the Julia iteration and defaults to zero', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.double({ min: -1, max: 1, noNaN: true }),  // z0 real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // z0 imag
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (real, imag, z0Real, z0Imag, maxIterations) => {
+          // Starting from z0 with parameter c is the Julia iteration of c from z0
+          expect(calculatePoint(real, imag, maxIterations, 2.0, z0Real, z0Imag))
+            .toBe(calculateJuliaPoint(z0Real, z0Imag, real, imag, maxIterations, 2.0));
+          expect(calculatePoint(real, imag, maxIterations, 2.0, 0, 0)).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+          expect(calculatePoint(real, imag, maxIterations, 2.0, 0, 0, true)).toBe(calculatePoint(real, imag, maxIterations, 2.0, true));
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3e: Buddhabrot accumulation counts in-view orbit values of escaping points
+  test('Property 3e: accumulateBuddhabrot increments one cell per in-view orbit value of escaping points', () => {
+    const width = 40, height = 30, scale = 0.1;
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1, noNaN: true }),   // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 200 }),                // max_iterations
+        (real, imag, maxIterations) => {
+          const densityBuf = new Uint32Array(width * height);
+          const written = accumulateBuddhabrot(real, imag, maxIterations, 2.0, width, height, -0.5, 0, scale, densityBuf);
+
+          const total = densityBuf.reduce((sum, value) => sum + value, 0);
+          expect(total).toBe(written);
+
+          // Escaping orbits have at most one value per iteration; others add nothing
+          const iterations = calculatePoint(real, imag, maxIterations, 2.0);
+          if (iterations >= maxIterations) {
+            expect(written).toBe(0);
+          } else {
+            expect(written).toBeLessThanOrEqual(iterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // c = 1 escapes as 1, 2, 5; with the view centered at 0 and scale 1,
+    // z_1 = 1 and z_2 = 2 land on pixels (4, 2) and (5, 2) of a 6x4 view
+    const densityBuf = new Uint32Array(24);
+    expect(accumulateBuddhabrot(1, 0, 100, 2.0, 6, 4, 0, 0, 1, densityBuf)).toBe(2);
+    expect(densityBuf[2 * 6 + 4]).toBe(1);
+    expect(densityBuf[2 * 6 + 5]).toBe(1);
+
+    // Accumulation adds to what is already in the buffer
+    expect(accumulateBuddhabrot(1, 0, 100, 2.0, 6, 4, 0, 0, 1, densityBuf)).toBe(2);
+    expect(densityBuf[2 * 6 + 4]).toBe(2);
+    expect(accumulateBuddhabrot(0, 0, 100, 2.0, 6, 4, 0, 0, 1, densityBuf)).toBe(0);
+    expect(accumulateBuddhabrot(1, 0, 100, 2.0, 6, 4, 0, 0, 1, new Uint32Array(23))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2f: Normalized counts are the iteration fraction
+  test('Property 2f: calculateNormalized returns iterations / maxIterations in [0, 1]', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        (real, imag, maxIterations) => {
+          const normalized = calculateNormalized(real, imag, maxIterations, 2.0);
+          const iterations = calculatePoint(real, imag, maxIterations, 2.0);
+
+          expect(normalized).toBeGreaterThanOrEqual(0);
+          expect(normalized).toBeLessThanOrEqual(1);
+          expect(normalized).toBe(iterations / maxIterations);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Interior points return exactly 1
+    expect(calculateNormalized(0, 0, 1000, 2.0)).toBe(1);
+    expect(calculateNormalized(-1, 0, 7, 2.0)).toBe(1);
+    expect(calculateNormalized(0, 0, 0, 2.0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 9a: shutdown releases a module instance
+  test('Property 9a: shutdown removes the exports and lets the Go program exit', async () => {
+    // Load a second instance so the one used by the other tests keeps running.
+    // Its registrations replace the globals, but the functions captured in
+    // beforeAll still belong to the first instance.
+    const go = new global.Go();
+    const wasmBuffer = await readFile(join(__dirname, '../../wasm/go/mandelbrot.wasm'));
+    const { instance } = await WebAssembly.instantiate(wasmBuffer, go.importObject);
+    const exited = go.run(instance);
+
+    const shutdown = global.shutdown;
+    expect(typeof global.calculatePoint).toBe('function');
+    expect(shutdown()).toBe(true);
+    await exited;
+
+    expect(global.calculatePoint).toBeUndefined();
+    expect(global.shutdown).toBeUndefined();
+    expect(go.exited).toBe(true);
+
+    // The first instance is unaffected
+    expect(calculatePoint(0, 0, 100, 2.0)).toBe(100);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2g: Multibrot smoothing uses log base power
+  test('Property 2g: smooth multibrot counts use log base power and match calculatePoint for power 2', () => {
+    // Iterate z = z^power + c in JS the same way the Go code does
+    const escape = (real, imag, power, maxIterations) => {
+      let zReal = 0, zImag = 0;
+      for (let iteration = 0; iteration < maxIterations; iteration++) {
+        const magnitudeSquared = zReal * zReal + zImag * zImag;
+        if (magnitudeSquared > 4) return { iteration, magnitudeSquared };
+        let resultReal = zReal, resultImag = zImag;
+        for (let i = 1; i < power; i++) {
+          [resultReal, resultImag] = [resultReal * zReal - resultImag * zImag, resultReal * zImag + resultImag * zReal];
+        }
+        zReal = resultReal + real;
+        zImag = resultImag + imag;
+      }
+      return null;
+    };
+
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2, max: 2, noNaN: true }),  // real component
+        fc.double({ min: -2, max: 2, noNaN: true }),  // imaginary component
+        fc.integer({ min: 2, max: 6 }),               // power
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        (real, imag, power, maxIterations) => {
+          const smooth = calculateMultibrotPoint(real, imag, power, maxIterations, 2.0, true);
+          const escaped = escape(real, imag, power, maxIterations);
+          if (escaped === null) {
+            expect(smooth).toBe(maxIterations);
+          } else {
+            const expected = escaped.iteration + 1 - Math.log(Math.log(escaped.magnitudeSquared) / 2) / Math.log(power);
+            expect(smooth).toBeCloseTo(expected, 9);
+          }
+
+          if (power === 2) {
+            expect(smooth).toBe(calculatePoint(real, imag, maxIterations, 2.0, true));
+          }
+          expect(calculateMultibrotPoint(real, imag, power, maxIterations, 2.0, false))
+            .toBe(calculateMultibrotPoint(real, imag, power, maxIterations, 2.0));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Structured results use the same power-aware smooth count
+    const smooth = calculateMultibrotPoint(1, 0.5, 3, 100, 2.0, true);
+    expect(smooth).toBeLessThan(100);
+    setStructuredResults(true);
+    try {
+      expect(calculateMultibrotPoint(1, 0.5, 3, 100, 2.0).smooth).toBe(smooth);
+    } finally {
+      setStructuredResults(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4r: Batch range covers escaped points only
+  test('Property 4r: calculateMandelbrotSet with withRange reports the escaped min and max', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.tuple(fc.double({ min: -3, max: 3, noNaN: true }), fc.double({ min: -3, max: 3, noNaN: true })), { minLength: 1, maxLength: 100 }),
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (points, maxIterations) => {
+          const realCoords = points.map(([real]) => real);
+          const imagCoords = points.map(([, imag]) => imag);
+          const { results, min, max } = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, 2.0, true);
+
+          expect(Array.from(results)).toEqual(Array.from(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, 2.0)));
+
+          const escaped = Array.from(results).filter((iterations) => iterations < maxIterations);
+          if (escaped.length === 0) {
+            expect(min).toBeNull();
+            expect(max).toBeNull();
+          } else {
+            expect(min).toBe(Math.min(...escaped));
+            expect(max).toBe(Math.max(...escaped));
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Interior points don't widen the range
+    const { min, max } = calculateMandelbrotSet([0, 3, 0.5], [0, 0, 0], 100, 2.0, true);
+    expect(min).toBe(calculatePoint(3, 0, 100, 2.0));
+    expect(max).toBe(calculatePoint(0.5, 0, 100, 2.0));
+    expect(max).toBeLessThan(100);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2h: Membership matches the escape-time count
+  test('Property 2h: isInSet agrees with calculatePoint reaching maxIterations', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1, noNaN: true }),   // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 500 }),                // max_iterations
+        (real, imag, maxIterations) => {
+          expect(isInSet(real, imag, maxIterations)).toBe(calculatePoint(real, imag, maxIterations, 2.0) === maxIterations);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(isInSet(0, 0, 1000)).toBe(true);
+    expect(isInSet(-1, 0, 1000)).toBe(true);
+    expect(isInSet(-0.122, 0.745, 1000)).toBe(true); // period-3 bulb, found by cycle detection
+    expect(isInSet(1, 1, 1000)).toBe(false);
+    expect(isInSet(0, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4s: Map tiles compose into the parent region
+  test('Property 4s: renderTile tiles cover their region and compose without seams', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),   // tile size
+        fc.integer({ min: 1, max: 200 }),  // max_iterations
+        (tileSize, maxIterations) => {
+          // Zoom 0 is the whole base square, real [-2.5, 1] and imag [-1.75, 1.75]
+          const tile = new Uint32Array(tileSize * tileSize);
+          expect(renderTile(0, 0, 0, tileSize, maxIterations, 2.0, tile)).toBe(tileSize * tileSize);
+          const view = new Uint32Array(tileSize * tileSize);
+          renderViewport(tileSize, tileSize, -0.75, 0, 3.5 / tileSize, maxIterations, 2.0, view);
+          expect(Array.from(tile)).toEqual(Array.from(view));
+
+          // The four zoom 1 tiles, placed side by side, match a viewport of
+          // twice the size over the same square
+          const size = 2 * tileSize;
+          const whole = new Uint32Array(size * size);
+          renderViewport(size, size, -0.75, 0, 3.5 / size, maxIterations, 2.0, whole);
+          for (let tileY = 0; tileY < 2; tileY++) {
+            for (let tileX = 0; tileX < 2; tileX++) {
+              renderTile(tileX, tileY, 1, tileSize, maxIterations, 2.0, tile);
+              for (let y = 0; y < tileSize; y++) {
+                for (let x = 0; x < tileSize; x++) {
+                  expect(tile[y * tileSize + x]).toBe(whole[(tileY * tileSize + y) * size + tileX * tileSize + x]);
+                }
+              }
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    const buf = new Uint32Array(16);
+    expect(renderTile(2, 0, 1, 4, 100, 2.0, buf)).toHaveProperty('error');
+    expect(renderTile(0, -1, 1, 4, 100, 2.0, buf)).toHaveProperty('error');
+    expect(renderTile(0, 0, 53, 4, 100, 2.0, buf)).toHaveProperty('error');
+    expect(renderTile(0, 0, 0, 5, 100, 2.0, buf)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2i: Coordinate-only calls use the stored defaults
+  test('Property 2i: setDefaults supplies maxIterations and escapeRadius to coordinate-only calls', () => {
+    try {
+      // Initial defaults match the JS render engine
+      expect(calculatePoint(-0.75, 0.1)).toBe(calculatePoint(-0.75, 0.1, 256, 2.0));
+
+      fc.assert(
+        fc.property(
+          fc.array(fc.tuple(fc.double({ min: -3, max: 3, noNaN: true }), fc.double({ min: -3, max: 3, noNaN: true })), { minLength: 1, maxLength: 50 }),
+          fc.integer({ min: 1, max: 1000 }),             // max_iterations
+          fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+          (points, maxIterations, escapeRadius) => {
+            expect(setDefaults(maxIterations, escapeRadius)).toBe(true);
+
+            const realCoords = points.map(([real]) => real);
+            const imagCoords = points.map(([, imag]) => imag);
+            expect(Array.from(calculateMandelbrotSet(realCoords, imagCoords)))
+              .toEqual(Array.from(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius)));
+            for (const [real, imag] of points) {
+              expect(calculatePoint(real, imag)).toBe(calculatePoint(real, imag, maxIterations, escapeRadius));
+            }
+          }
+        ),
+        { numRuns: 50 }
+      );
+
+      // Invalid defaults are rejected and leave the stored ones unchanged
+      setDefaults(100, 2.0);
+      expect(setDefaults(0, 2.0)).toHaveProperty('error');
+      expect(setDefaults(100, -1)).toHaveProperty('error');
+      expect(calculatePoint(0, 0)).toBe(100);
+    } finally {
+      setDefaults(256, 2.0);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5h: Log-scaled smooth coloring stays within the palette
+  test('Property 5h: renderRGBASmooth colors escaped points between palette colors and interior black', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        fc.integer({ min: 1, max: 255 }),             // gray level of the last palette color
+        (width, height, centerReal, centerImag, scale, maxIterations, level) => {
+          const pixels = width * height;
+          const rgbaBuf = new Uint8ClampedArray(pixels * 4);
+          const palette = new Uint8Array([0, 0, 0, level, level, level]);
+          expect(renderRGBASmooth(width, height, centerReal, centerImag, scale, maxIterations, 2.0, rgbaBuf, palette)).toBe(pixels);
+
+          const iterations = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, iterations);
+
+          for (let i = 0; i < pixels; i++) {
+            expect(rgbaBuf[i * 4 + 3]).toBe(255);
+            expect(rgbaBuf[i * 4 + 1]).toBe(rgbaBuf[i * 4]);
+            expect(rgbaBuf[i * 4 + 2]).toBe(rgbaBuf[i * 4]);
+            expect(rgbaBuf[i * 4]).toBeLessThanOrEqual(level);
+            if (iterations[i] === maxIterations) {
+              expect(rgbaBuf[i * 4]).toBe(0);
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // A palette of one repeated color paints every escaped point with it
+    const rgbaBuf = new Uint8ClampedArray(4);
+    renderRGBASmooth(1, 1, 2, 2, 0.01, 100, 2.0, rgbaBuf, new Uint8Array([10, 20, 30, 10, 20, 30]));
+    expect(Array.from(rgbaBuf)).toEqual([10, 20, 30, 255]);
+
+    expect(renderRGBASmooth(1, 1, 0, 0, 0.01, 100, 2.0, rgbaBuf, new Uint8Array([1, 2, 3]))).toHaveProperty('error');
+    expect(renderRGBASmooth(1, 1, 0, 0, 0.01, 100, 2.0, rgbaBuf, new Uint8Array(7))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4t: Pixel aspect scales the imaginary axis only
+  test('Property 4t: renderViewport with aspect spaces rows by scale * aspect', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.double({ min: 0.25, max: 4, noNaN: true }),    // aspect
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, aspect, maxIterations) => {
+          const resultBuf = new Uint32Array(width * height);
+          const written = renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf, false, 4, aspect);
+          expect(written).toBe(width * height);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const real = centerReal + (x - width / 2) * scale;
+              const imag = centerImag - (y - height / 2) * (scale * aspect);
+              expect(resultBuf[y * width + x]).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Aspect 1 reproduces square pixels
+    const square = new Uint32Array(64);
+    const explicit = new Uint32Array(64);
+    renderViewport(8, 8, -0.5, 0, 0.3, 100, 2.0, square);
+    renderViewport(8, 8, -0.5, 0, 0.3, 100, 2.0, explicit, false, 4, 1);
+    expect(Array.from(explicit)).toEqual(Array.from(square));
+
+    expect(renderViewport(8, 8, -0.5, 0, 0.3, 100, 2.0, explicit, false, 4, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5i: Rotated-grid supersampling is selectable alongside the grid
+  test('Property 5i: renderRGBA pattern "rotated" takes four RGSS samples and "grid" matches the default', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),              // width
+        fc.integer({ min: 1, max: 12 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        (width, height, centerReal, centerImag, scale) => {
+          const pixels = width * height;
+          const grid = new Uint8ClampedArray(pixels * 4);
+          const explicitGrid = new Uint8ClampedArray(pixels * 4);
+          const rotated = new Uint8ClampedArray(pixels * 4);
+
+          renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, grid, 2);
+          renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, explicitGrid, 2, 'grid');
+          expect(renderRGBA(width, height, centerReal, centerImag, scale, 200, 2.0, rotated, 2, 'rotated')).toBe(pixels);
+          expect(Array.from(explicitGrid)).toEqual(Array.from(grid));
+
+          for (let i = 0; i < pixels; i++) {
+            expect(rotated[i * 4 + 3]).toBe(255);
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Every sample of a view inside the main cardioid is interior
+    const interior = new Uint8ClampedArray(4 * 4 * 4);
+    renderRGBA(4, 4, -0.2, 0, 0.01, 200, 2.0, interior, 2, 'rotated');
+    for (let i = 0; i < 16; i++) {
+      expect(interior[i * 4] + interior[i * 4 + 1] + interior[i * 4 + 2]).toBe(0);
+    }
+
+    expect(renderRGBA(4, 4, -0.2, 0, 0.01, 200, 2.0, interior, 3, 'rotated')).toHaveProperty('error');
+    expect(renderRGBA(4, 4, -0.2, 0, 0.01, 200, 2.0, interior, 2, 'jittered')).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4u: ArrayBuffer batch holds the array batch as little-endian uint32s
+  test('Property 4u: calculateMandelbrotSetBuffer returns a transferable ArrayBuffer of the batch counts', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.tuple(fc.double({ min: -3, max: 3, noNaN: true }), fc.double({ min: -3, max: 3, noNaN: true })), { minLength: 1, maxLength: 100 }),
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (points, maxIterations, escapeRadius) => {
+          const realCoords = points.map(([real]) => real);
+          const imagCoords = points.map(([, imag]) => imag);
+          const buffer = calculateMandelbrotSetBuffer(realCoords, imagCoords, maxIterations, escapeRadius);
+
+          expect(buffer).toBeInstanceOf(ArrayBuffer);
+          expect(buffer.byteLength).toBe(4 * points.length);
+
+          const expected = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius);
+          const view = new DataView(buffer);
+          for (let i = 0; i < points.length; i++) {
+            expect(view.getUint32(4 * i, true)).toBe(expected[i]);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Transferring moves the buffer instead of copying it
+    const buffer = calculateMandelbrotSetBuffer([0, 2], [0, 2], 100, 2.0);
+    const moved = structuredClone(buffer, { transfer: [buffer] });
+    expect(buffer.byteLength).toBe(0);
+    expect(Array.from(new Uint32Array(moved))).toEqual([100, 1]);
+
+    expect(calculateMandelbrotSetBuffer([0], [0], 0, 2.0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4v: Generated coordinates are the renderViewport sample points
+  test('Property 4v: generateCoordinates feeds the batch functions the same points renderViewport uses', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 1e-15, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const pixels = width * height;
+          const realBuf = new Float64Array(pixels);
+          const imagBuf = new Float64Array(pixels);
+          expect(generateCoordinates(width, height, centerReal, centerImag, scale, realBuf, imagBuf)).toBe(pixels);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              expect(realBuf[y * width + x]).toBe(centerReal + (x - width / 2) * scale);
+              expect(imagBuf[y * width + x]).toBe(centerImag - (y - height / 2) * scale);
+            }
+          }
+
+          const batch = new Uint32Array(pixels);
+          const viewport = new Uint32Array(pixels);
+          calculateMandelbrotSetTyped(realBuf, imagBuf, batch, maxIterations, 2.0);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, viewport);
+          expect(Array.from(batch)).toEqual(Array.from(viewport));
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(generateCoordinates(4, 4, 0, 0, 0.1, new Float64Array(15), new Float64Array(16))).toHaveProperty('error');
+    expect(generateCoordinates(4, 4, 0, 0, 0.1, new Float32Array(16), new Float64Array(16))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3f: Distance estimates bound the distance to the set
+  test('Property 3f: calculateDistanceEstimate is consistent with pixel normalization and the set bounds', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1e-6, max: 1, noNaN: true }), // pixel scale
+        (real, imag, maxIterations, pixelScale) => {
+          const distance = calculateDistanceEstimate(real, imag, maxIterations, 1000);
+          const pixels = calculateDistanceEstimate(real, imag, maxIterations, 1000, pixelScale);
+
+          expect(distance).toBeGreaterThanOrEqual(0);
+          expect(pixels).toBeCloseTo(Math.min(1, distance / pixelScale), 12);
+          if (calculatePoint(real, imag, maxIterations, 1000) === maxIterations) {
+            expect(distance).toBe(0);
+          }
+
+          // 0 is in the set, so the true distance, at least a quarter of the estimate, is at most |c|
+          expect(distance / 4).toBeLessThanOrEqual(Math.hypot(real, imag) + 1e-9);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // c = 3 escapes at once with z = 3 and dz = 1
+    expect(calculateDistanceEstimate(3, 0, 100, 2.0)).toBeCloseTo(6 * Math.log(3), 12);
+    expect(calculateDistanceEstimate(3, 0, 100, 2.0, 0.001)).toBe(1);
+    expect(calculateDistanceEstimate(3, 0, 100, 2.0, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4w: Budgeted slices resume into the full viewport
+  test('Property 4w: renderViewportBudget stops at the budget and resumes into renderViewport output', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        fc.integer({ min: 1, max: 5000 }),            // budget
+        (width, height, centerReal, centerImag, scale, maxIterations, budget) => {
+          const pixels = width * height;
+          const expected = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected);
+
+          const resultBuf = new Uint32Array(pixels);
+          let next = 0;
+          while (next < pixels) {
+            const start = next;
+            next = renderViewportBudget(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf, budget, start);
+            expect(next).toBeGreaterThan(start);
+            expect(next).toBeLessThanOrEqual(pixels);
+
+            // The budget was not yet reached before the last pixel of the slice
+            let spent = 0;
+            for (let i = start; i < next - 1; i++) {
+              spent += expected[i];
+            }
+            expect(spent).toBeLessThan(budget);
+            if (next < pixels) {
+              expect(spent + expected[next - 1]).toBeGreaterThanOrEqual(budget);
+            }
+          }
+          expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    const resultBuf = new Uint32Array(16);
+    expect(renderViewportBudget(4, 4, 0, 0, 0.1, 100, 2.0, resultBuf, 1000, 16)).toBe(16);
+    expect(renderViewportBudget(4, 4, 0, 0, 0.1, 100, 2.0, resultBuf, 1000, 17)).toHaveProperty('error');
+    expect(renderViewportBudget(4, 4, 0, 0, 0.1, 100, 2.0, resultBuf, 0, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 6b: Reported concurrency is a usable worker count
+  test('Property 6b: getConcurrency returns a positive integer accepted by setWorkerCount', () => {
+    const concurrency = getConcurrency();
+    expect(Number.isInteger(concurrency)).toBe(true);
+    expect(concurrency).toBeGreaterThanOrEqual(1);
+
+    try {
+      expect(setWorkerCount(concurrency)).toBe(true);
+    } finally {
+      setWorkerCount(1);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4x: Fixed-point renders follow exact Q4.60 integer arithmetic
+  test('Property 4x: setFixedPoint renders match a BigInt Q4.60 reference bit for bit', () => {
+    const ONE = 1n << 60n;
+    const toFixed = (x) => BigInt(Math.sign(x) * Math.round(Math.abs(x) * 2 ** 60));
+    const abs = (x) => (x < 0n ? -x : x);
+    const mul = (a, b) => {
+      const product = (abs(a) * abs(b)) >> 60n;
+      return (a < 0n) !== (b < 0n) ? -product : product;
+    };
+    const referenceEscapeTime = (cReal, cImag, maxIterations) => {
+      if (Math.abs(cReal) > 2 || Math.abs(cImag) > 2) {
+        return 1;
+      }
+      const radius = 2n * ONE;
+      const radiusSquared = 4n * ONE;
+      const cr = toFixed(cReal);
+      const ci = toFixed(cImag);
+      let zr = 0n;
+      let zi = 0n;
+      for (let iteration = 0; iteration < maxIterations; iteration++) {
+        if (abs(zr) > radius || abs(zi) > radius) {
+          return iteration;
+        }
+        const zr2 = mul(zr, zr);
+        const zi2 = mul(zi, zi);
+        if (zr2 + zi2 > radiusSquared) {
+          return iteration;
+        }
+        zi = 2n * mul(zr, zi) + ci;
+        zr = zr2 - zi2 + cr;
+      }
+      return maxIterations;
+    };
+
+    try {
+      expect(setFixedPoint(true)).toBe(true);
+      fc.assert(
+        fc.property(
+          fc.integer({ min: 1, max: 8 }),               // width
+          fc.integer({ min: 1, max: 8 }),               // height
+          fc.double({ min: -2, max: 0.5, noNaN: true }), // center real
+          fc.double({ min: -1.2, max: 1.2, noNaN: true }), // center imag
+          fc.double({ min: 1e-12, max: 0.5, noNaN: true }), // scale
+          fc.integer({ min: 1, max: 300 }),              // max_iterations
+          (width, height, centerReal, centerImag, scale, maxIterations) => {
+            const resultBuf = new Uint32Array(width * height);
+            renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf);
+
+            for (let y = 0; y < height; y++) {
+              for (let x = 0; x < width; x++) {
+                const real = centerReal + (x - width / 2) * scale;
+                const imag = centerImag - (y - height / 2) * scale;
+                expect(resultBuf[y * width + x]).toBe(referenceEscapeTime(real, imag, maxIterations));
+              }
+            }
+          }
+        ),
+        { numRuns: 50 }
+      );
+    } finally {
+      setFixedPoint(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4y: Interior mask bits mark the pixels at maxIterations
+  test('Property 4y: renderToMemory with maskOffset packs interior pixels one bit each, LSB first', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        fc.constantFrom(1, 2, 4),                     // bytes per pixel
+        fc.boolean(),                                 // column major
+        (width, height, centerReal, centerImag, scale, maxIterations, bytesPerPixel, columnMajor) => {
+          const pixels = width * height;
+          const maskLength = Math.ceil(pixels / 8);
+          const countsLength = Math.ceil((pixels * bytesPerPixel) / 4) * 4;
+          const offset = getMemoryBuffer(countsLength + maskLength);
+          const maskOffset = offset + countsLength;
+
+          // Pre-fill the mask so stale bits would show
+          new Uint8Array(wasmMemory.buffer, maskOffset, maskLength).fill(0xff);
+          expect(renderToMemory(offset, width, height, centerReal, centerImag, scale, maxIterations, 2.0, columnMajor, bytesPerPixel, maskOffset)).toBe(pixels);
+
+          const mask = new Uint8Array(wasmMemory.buffer, maskOffset, maskLength).slice();
+          const counts = new Uint32Array(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, counts, columnMajor);
+          for (let i = 0; i < maskLength * 8; i++) {
+            const bit = (mask[i >> 3] >> (i & 7)) & 1;
+            expect(bit).toBe(i < pixels && counts[i] === maxIterations ? 1 : 0);
+          }
+
+          // The mask may not overlap the counts
+          expect(renderToMemory(offset, width, height, centerReal, centerImag, scale, maxIterations, 2.0, columnMajor, bytesPerPixel, offset)).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 50 }
+    );
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4z: Guessed pixels copy a verified uniform neighborhood
+  test('Property 4z: setNeighborGuessing only fills midpoints of verified uniform neighborhoods', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.integer({ min: 1, max: 24 }),              // width
+          fc.integer({ min: 1, max: 24 }),              // height
+          fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+          fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+          fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+          fc.integer({ min: 1, max: 300 }),             // max_iterations
+          (width, height, centerReal, centerImag, scale, maxIterations) => {
+            const exact = new Uint32Array(width * height);
+            renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, exact);
+
+            expect(setNeighborGuessing(true)).toBe(true);
+            const guessed = new Uint32Array(width * height);
+            expect(renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, guessed)).toBe(width * height);
+            setNeighborGuessing(false);
+
+            const at = (buf, x, y) => buf[y * width + x];
+            for (let y = 0; y < height; y++) {
+              for (let x = 0; x < width; x++) {
+                if (at(guessed, x, y) === at(exact, x, y)) {
+                  continue;
+                }
+
+                // A differing pixel must be an edge midpoint whose neighborhood
+                // corners and center all hold the guessed value
+                const midpointOfRow = y % 2 === 0 && x % 2 === 1;
+                const x0 = midpointOfRow ? x - 1 : x;
+                const y0 = midpointOfRow ? y : y - 1;
+                expect(midpointOfRow || (x % 2 === 0 && y % 2 === 1)).toBe(true);
+                const value = at(guessed, x, y);
+                for (const [dx, dy] of [[0, 0], [2, 0], [0, 2], [2, 2], [1, 1]]) {
+                  expect(at(exact, x0 + dx, y0 + dy)).toBe(value);
+                }
+              }
+            }
+          }
+        ),
+        { numRuns: 50 }
+      );
+
+      // Inside the main cardioid every guess is exact
+      const exact = new Uint32Array(400);
+      const guessed = new Uint32Array(400);
+      renderViewport(20, 20, -0.2, 0, 0.005, 200, 2.0, exact);
+      setNeighborGuessing(true);
+      renderViewport(20, 20, -0.2, 0, 0.005, 200, 2.0, guessed);
+      expect(Array.from(guessed)).toEqual(Array.from(exact));
+    } finally {
+      setNeighborGuessing(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2j: Square bailout escapes when either component leaves the radius
+  test('Property 2j: setBailoutShape("square") matches a reference square escape test in single and batch calls', () => {
+    const squareEscapeTime = (cReal, cImag, maxIterations, escapeRadius) => {
+      let zReal = 0;
+      let zImag = 0;
+      for (let iteration = 0; iteration < maxIterations; iteration++) {
+        if (Math.abs(zReal) > escapeRadius || Math.abs(zImag) > escapeRadius) {
+          return iteration;
+        }
+        const zRealTemp = zReal * zReal - zImag * zImag + cReal;
+        zImag = 2.0 * zReal * zImag + cImag;
+        zReal = zRealTemp;
+      }
+      return maxIterations;
+    };
+
+    try {
+      fc.assert(
+        fc.property(
+          fc.array(fc.tuple(fc.double({ min: -3, max: 3, noNaN: true }), fc.double({ min: -3, max: 3, noNaN: true })), { minLength: 1, maxLength: 50 }),
+          fc.integer({ min: 1, max: 500 }),             // max_iterations
+          fc.double({ min: 2, max: 10, noNaN: true }),  // escape_radius
+          (points, maxIterations, escapeRadius) => {
+            const realCoords = points.map(([real]) => real);
+            const imagCoords = points.map(([, imag]) => imag);
+            const circle = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius);
+            const expected = points.map(([real, imag]) => squareEscapeTime(real, imag, maxIterations, escapeRadius));
+
+            // A bailoutShape argument selects the shape for one call only
+            expect(Array.from(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius, false, 'square'))).toEqual(expected);
+            const resultBuf = new Uint32Array(points.length);
+            calculateMandelbrotSetTyped(new Float64Array(realCoords), new Float64Array(imagCoords), resultBuf, maxIterations, escapeRadius, 'square');
+            expect(Array.from(resultBuf)).toEqual(expected);
+            const buffer = calculateMandelbrotSetBuffer(realCoords, imagCoords, maxIterations, escapeRadius, 'square');
+            expect(Array.from(new Uint32Array(buffer))).toEqual(expected);
+            const interleaved = points.flat();
+            expect(Array.from(calculateMandelbrotInterleaved(interleaved, maxIterations, escapeRadius, 'square'))).toEqual(expected);
+            points.forEach(([real, imag], i) => {
+              expect(calculatePoint(real, imag, maxIterations, escapeRadius, 0, 0, false, 'square')).toBe(expected[i]);
+            });
+            expect(Array.from(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius))).toEqual(Array.from(circle));
+
+            expect(setBailoutShape('square')).toBe(true);
+            expect(Array.from(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius, false, 'circle'))).toEqual(Array.from(circle));
+            const square = calculateMandelbrotSet(realCoords, imagCoords, maxIterations, escapeRadius);
+            points.forEach(([real, imag], i) => {
+              const expected = squareEscapeTime(real, imag, maxIterations, escapeRadius);
+              expect(square[i]).toBe(expected);
+              expect(calculatePoint(real, imag, maxIterations, escapeRadius)).toBe(expected);
+              expect(square[i]).toBeGreaterThanOrEqual(circle[i]);
+            });
+            setBailoutShape('circle');
+          }
+        ),
+        { numRuns: 50 }
+      );
+
+      // |c|^2 = 4.5 leaves the circle of radius 2 at once, but both components are inside the square
+      expect(calculatePoint(1.5, 1.5, 100, 2.0)).toBe(1);
+      setBailoutShape('square');
+      expect(calculatePoint(1.5, 1.5, 100, 2.0)).toBe(2);
+      expect(calculatePoint(1.5, 1.5, 100, 2.0, 0, 0, false, null)).toBe(2);
+      expect(calculatePoint(1.5, 1.5, 100, 2.0, 0, 0, false, 'circle')).toBe(1);
+      expect(setBailoutShape('diamond')).toHaveProperty('error');
+      expect(calculatePoint(1.5, 1.5, 100, 2.0, 0, 0, false, 'diamond')).toHaveProperty('error');
+      expect(calculateMandelbrotSet([0], [0], 100, 2.0, false, 1)).toHaveProperty('error');
+    } finally {
+      setBailoutShape('circle');
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5j: Float32 smooth output is the float64 smooth count rounded once
+  test('Property 5j: renderSmoothFloat32 writes Math.fround of the smooth calculatePoint value per pixel', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const resultBuf = new Float32Array(width * height);
+          expect(renderSmoothFloat32(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf)).toBe(width * height);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const real = centerReal + (x - width / 2) * scale;
+              const imag = centerImag - (y - height / 2) * scale;
+              expect(resultBuf[y * width + x]).toBe(Math.fround(calculatePoint(real, imag, maxIterations, 2.0, true)));
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(renderSmoothFloat32(2, 2, 0, 0, 0.1, 100, 2.0, new Float64Array(4))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4aa: Exponential map pixels sample center + baseRadius * exp(...)
+  test('Property 4aa: renderExponentialMap samples log-radius columns and angle rows around the center', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 0.5, noNaN: true }), // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 1e-6, max: 1, noNaN: true }), // base radius
+        fc.integer({ min: 1, max: 300 }),             // max_iterations
+        (width, height, centerReal, centerImag, baseRadius, maxIterations) => {
+          const resultBuf = new Uint32Array(width * height);
+          expect(renderExponentialMap(width, height, centerReal, centerImag, baseRadius, maxIterations, 2.0, resultBuf)).toBe(width * height);
+
+          let mismatches = 0;
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const radius = baseRadius * Math.exp((2 * Math.PI * x) / height);
+              const angle = (2 * Math.PI * y) / height;
+              const real = centerReal + radius * Math.cos(angle);
+              const imag = centerImag + radius * Math.sin(angle);
+              if (resultBuf[y * width + x] !== calculatePoint(real, imag, maxIterations, 2.0)) {
+                mismatches++;
+              }
+            }
+          }
+
+          // Go and JS may round exp, sin and cos differently in the last bit,
+          // which can only change pixels lying on a band edge
+          expect(mismatches).toBeLessThanOrEqual(Math.ceil((width * height) / 50));
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(renderExponentialMap(4, 4, 0, 0, 0, 100, 2.0, new Uint32Array(16))).toHaveProperty('error');
+    // width*height would wrap around in an int; the size is rejected instead
+    expect(renderExponentialMap(4294967297, 4294967295, 0, 0, 1, 10, 2, new Uint32Array(4))).toHaveProperty('error');
+    expect(renderExponentialMap(16385, 16384, 0, 0, 1, 10, 2, new Uint32Array(4))).toHaveProperty('error');
+    expect(renderExponentialMap(4, 4, 0, 0, 1, 10, 2.0, new Uint32Array(16))).toBe(16);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3g: Linear escape time lies in the step where the orbit crosses the radius
+  test('Property 3g: calculateLinearEscapeTime interpolates the radius crossing and returns -1 for interior points', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        fc.double({ min: 1.5, max: 10, noNaN: true }), // escape_radius
+        (real, imag, maxIterations, escapeRadius) => {
+          const escapeTime = calculateLinearEscapeTime(real, imag, maxIterations, escapeRadius);
+          const iterations = calculatePoint(real, imag, maxIterations, escapeRadius);
+          if (iterations === maxIterations) {
+            expect(escapeTime).toBe(-1);
+          } else {
+            expect(escapeTime).toBeGreaterThan(iterations - 1);
+            expect(escapeTime).toBeLessThanOrEqual(iterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // c = 3: |z_0| = 0 and |z_1| = 3 cross radius 2 two thirds of the way
+    expect(calculateLinearEscapeTime(3, 0, 100, 2.0)).toBeCloseTo(2 / 3, 12);
+    // c = 1.5: |z_1| = 1.5 stays inside and |z_2| = 3.75 crosses 0.5 / 2.25 of the way
+    expect(calculateLinearEscapeTime(1.5, 0, 100, 2.0)).toBeCloseTo(1 + 0.5 / 2.25, 12);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ab: Stateful renderer steps converge to the full viewport
+  test('Property 4ab: renderStep previews sharpen over four passes into renderViewport output', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 20 }),              // width
+        fc.integer({ min: 1, max: 20 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.5, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const expected = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected);
+
+          const renderer = createRenderer(width, height, centerReal, centerImag, scale, maxIterations, 2.0);
+          expect(Array.from(getBuffer(renderer)).every((value) => value === 0)).toBe(true);
+
+          for (const stride of [8, 4, 2, 1]) {
+            expect(renderStep(renderer)).toBe(stride > 1);
+
+            // Every pixel shows the count of the grid pixel of its block
+            const buffer = getBuffer(renderer);
+            for (let y = 0; y < height; y++) {
+              for (let x = 0; x < width; x++) {
+                const source = (y - (y % stride)) * width + x - (x % stride);
+                expect(buffer[y * width + x]).toBe(expected[source]);
+              }
+            }
+          }
+          expect(renderStep(renderer)).toBe(false);
+          expect(destroyRenderer(renderer)).toBe(true);
+          expect(getBuffer(renderer)).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 30 }
+    );
+
+    expect(renderStep(-1)).toHaveProperty('error');
+    expect(createRenderer(0, 4, 0, 0, 0.1, 100, 2.0)).toHaveProperty('error');
+    // Oversized renderers are rejected instead of allocated
+    expect(createRenderer(1e7, 1e7, -0.5, 0, 0.01, 10, 2)).toHaveProperty('error');
+    expect(checkPrecision(1e7, 1e7, -0.5, 0, 0.01)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2k: Non-positive maxIterations iterates until escape
+  test('Property 2k: calculatePoint with maxIterations <= 0 matches the 100000 iteration safety cap', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1.5, noNaN: true }), // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: -100, max: 0 }),               // non-positive max_iterations
+        (real, imag, maxIterations) => {
+          const capped = calculatePoint(real, imag, 100000, 2.0);
+          expect(calculatePoint(real, imag, maxIterations, 2.0)).toBe(capped);
+          expect(calculatePoint(real, imag, maxIterations, 2.0, true)).toBe(calculatePoint(real, imag, 100000, 2.0, true));
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Interior points stop at the cap, escaping points at their escape
+    expect(calculatePoint(0, 0, 0, 2.0)).toBe(100000);
+    expect(calculatePoint(0.25, 0.5, -1, 2.0)).toBe(calculatePoint(0.25, 0.5, 100000, 2.0));
+    expect(calculatePoint(2, 2, 0, 2.0)).toBe(1);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3h: Escape phase is the angle of the escaping orbit value
+  test('Property 3h: calculatePointWithPhase returns the angle of the last orbit point, NaN for interior points', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1.5, noNaN: true }), // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 500 }),                // max_iterations
+        (real, imag, maxIterations) => {
+          const result = calculatePointWithPhase(real, imag, maxIterations, 2.0);
+          expect(result.iterations).toBe(calculatePointWithMagnitude(real, imag, maxIterations, 2.0).iterations);
+          if (result.iterations === maxIterations) {
+            expect(result.phase).toBeNaN();
+          } else {
+            const orbit = calculateOrbit(real, imag, maxIterations, 2.0, maxIterations);
+            const last = orbit.length - 2;
+            expect(result.phase).toBeCloseTo(Math.atan2(orbit[last + 1], orbit[last]), 12);
+            expect(Math.abs(result.phase)).toBeLessThanOrEqual(Math.PI);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // c = -3: z_1 = -3 escapes on the negative real axis
+    expect(calculatePointWithPhase(-3, 0, 100, 2.0).phase).toBe(Math.PI);
+    expect(calculatePointWithPhase(0, 3, 100, 2.0).phase).toBe(Math.PI / 2);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ac: Region statistics summarize a coarse viewport grid
+  test('Property 4ac: regionStats matches statistics of the equivalent coarse renderViewport', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 4, noNaN: true }), // region size
+        fc.integer({ min: 1, max: 300 }),             // sample count
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (centerReal, centerImag, scale, sampleCount, maxIterations) => {
+          const side = Math.floor(Math.sqrt(sampleCount));
+          const expected = new Uint32Array(side * side);
+          renderViewport(side, side, centerReal, centerImag, scale / side, maxIterations, 2.0, expected);
+
+          const escaped = Array.from(expected).filter((n) => n < maxIterations);
+          const stats = regionStats(centerReal, centerImag, scale, sampleCount, maxIterations, 2.0);
+          expect(stats.interiorFraction).toBeCloseTo(1 - escaped.length / expected.length, 12);
+          expect(stats.max).toBe(escaped.length > 0 ? Math.max(...escaped) : 0);
+          const mean = escaped.length > 0 ? escaped.reduce((a, b) => a + b, 0) / escaped.length : 0;
+          expect(stats.mean).toBeCloseTo(mean, 9);
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // A region inside the main cardioid is all interior
+    expect(regionStats(-0.2, 0, 0.1, 16, 100, 2.0)).toEqual({ mean: 0, max: 0, interiorFraction: 1 });
+    expect(regionStats(0, 0, 0, 16, 100, 2.0)).toHaveProperty('error');
+    expect(regionStats(-0.5, 0, 3, 1e13, 10, 2)).toHaveProperty('error');
+    expect(regionStats(-0.5, 0, 3, 0, 10, 2)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ad: Series approximation skips iterations without changing the render
+  test('Property 4ad: renderPerturbation with series approximation matches the full perturbation render', () => {
+    const width = 48;
+    const height = 48;
+    const views = [
+      [-0.5, 0, 0.05, 200],                             // shallow: the series is never accurate enough
+      [0, 1, 1e-60, 1000],                               // Misiurewicz point far below float64 resolution
+      [-1.7499, 0, 1e-13, 5000],                         // real axis near the period-doubling limit
+      [-0.743643887037151, 0.13182590420533, 1e-12, 3000] // seahorse valley, many chaotic boundary pixels
+    ];
+
+    try {
+      for (const [centerReal, centerImag, scale, maxIterations] of views) {
+        const full = new Uint32Array(width * height);
+        const fullGlitches = new Uint8Array(width * height);
+        const skipped = new Uint32Array(width * height);
+        const skippedGlitches = new Uint8Array(width * height);
+
+        expect(setSeriesApproximation(false)).toBe(true);
+        renderPerturbation(width, height, centerReal, centerImag, scale, maxIterations, 2.0, full, fullGlitches);
+        expect(setSeriesApproximation(true)).toBe(true);
+        renderPerturbation(width, height, centerReal, centerImag, scale, maxIterations, 2.0, skipped, skippedGlitches);
+
+        const skip = calculateSeriesSkip(width, height, centerReal, centerImag, scale, maxIterations, 2.0);
+        expect(skip).toBeGreaterThanOrEqual(0);
+        expect(skip).toBeLessThanOrEqual(Math.min(...full));
+        if (scale < 1e-9) {
+          expect(skip).toBeGreaterThan(0);
+        }
+
+        // Only pixels on chaotic boundary orbits may end up a different count
+        let mismatches = 0;
+        for (let i = 0; i < width * height; i++) {
+          if (fullGlitches[i] === 0 && skippedGlitches[i] === 0 && full[i] !== skipped[i]) {
+            mismatches++;
+          }
+        }
+        expect(mismatches).toBeLessThanOrEqual(Math.ceil(width * height / 100));
+      }
+    } finally {
+      setSeriesApproximation(false);
+    }
+
+    expect(calculateSeriesSkip(48, 48, -0.5, 0, 0.05, 200, 2.0)).toBe(0);
+    expect(calculateSeriesSkip(48, 48, -0.5, 0, 0.05, 0, 2.0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2l: Escape radius 0 selects the bailout of the power
+  test('Property 2l: calculateMultibrotPoint with escapeRadius 0 uses max(|c|, 2^(1/(power-1)))', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2, max: 2, noNaN: true }), // real component
+        fc.double({ min: -2, max: 2, noNaN: true }), // imaginary component
+        fc.integer({ min: 2, max: 8 }),              // power
+        fc.integer({ min: 1, max: 300 }),            // max_iterations
+        (real, imag, power, maxIterations) => {
+          const radius = Math.max(Math.hypot(real, imag), Math.pow(2, 1 / (power - 1)));
+          expect(calculateMultibrotPoint(real, imag, power, maxIterations, 0)).toBe(calculateMultibrotPoint(real, imag, power, maxIterations, radius));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // The bailout is exact: a larger radius never changes whether a point escapes
+    for (const [real, imag] of [[0.6, 0.2], [-0.9, 0.1], [0.3, 0.8], [1.2, -1.1]]) {
+      const defaulted = calculateMultibrotPoint(real, imag, 5, 200, 0);
+      const wide = calculateMultibrotPoint(real, imag, 5, 200, 100);
+      expect(defaulted === 200).toBe(wide === 200);
+    }
+    expect(calculateMultibrotPoint(0, 0, 3, 100, -1)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ae: Interleaved batches match separate coordinate arrays
+  test('Property 4ae: calculateMandelbrotInterleaved matches calculateMandelbrotSet for arrays and Float64Arrays', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.tuple(fc.double({ min: -2.5, max: 1.5, noNaN: true }), fc.double({ min: -1.5, max: 1.5, noNaN: true })), { minLength: 1, maxLength: 50 }),
+        fc.integer({ min: 1, max: 500 }), // max_iterations
+        (points, maxIterations) => {
+          const expected = calculateMandelbrotSet(points.map(([re]) => re), points.map(([, im]) => im), maxIterations, 2.0);
+          const coords = points.flat();
+          expect(calculateMandelbrotInterleaved(coords, maxIterations, 2.0)).toEqual(expected);
+          expect(calculateMandelbrotInterleaved(new Float64Array(coords), maxIterations, 2.0)).toEqual(expected);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(calculateMandelbrotInterleaved([0, 0, 1], 100, 2.0)).toHaveProperty('error');
+    expect(calculateMandelbrotInterleaved([], 100, 2.0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4af: Precision limits flag unresolvable pixels
+  test('Property 4af: checkPrecision flags pixels whose step is below the precision of the active mode', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),               // width
+        fc.integer({ min: 1, max: 16 }),               // height
+        fc.double({ min: -2, max: 2, noNaN: true }),   // center real
+        fc.double({ min: -2, max: 2, noNaN: true }),   // center imag
+        fc.integer({ min: -40, max: -1 }),             // scale exponent
+        (width, height, centerReal, centerImag, exponent) => {
+          const scale = Math.pow(10, exponent);
+          const flagBuf = new Uint8Array(width * height);
+          const result = checkPrecision(width, height, centerReal, centerImag, scale, flagBuf);
+
+          const realBuf = new Float64Array(width * height);
+          const imagBuf = new Float64Array(width * height);
+          generateCoordinates(width, height, centerReal, centerImag, scale, realBuf, imagBuf);
+          let flagged = 0;
+          for (let i = 0; i < width * height; i++) {
+            const expected = scale < 1e-15 * Math.max(Math.abs(realBuf[i]), Math.abs(imagBuf[i]));
+            expect(flagBuf[i]).toBe(expected ? 1 : 0);
+            flagged += flagBuf[i];
+          }
+          expect(result).toEqual({ lowPrecision: flagged > 0, flagged });
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Double-double and fixed point move the limit
+    expect(checkPrecision(8, 8, -0.75, 0.1, 1e-20).lowPrecision).toBe(true);
+    try {
+      setHighPrecision(true);
+      expect(checkPrecision(8, 8, -0.75, 0.1, 1e-20).lowPrecision).toBe(false);
+      expect(checkPrecision(8, 8, -0.75, 0.1, 1e-32).flagged).toBe(64);
+    } finally {
+      setHighPrecision(false);
+    }
+    try {
+      setFixedPoint(true);
+      expect(checkPrecision(8, 8, 0, 0, 1e-16).lowPrecision).toBe(false);
+      expect(checkPrecision(8, 8, 0, 0, 1e-18).flagged).toBe(64);
+    } finally {
+      setFixedPoint(false);
+    }
+
+    expect(checkPrecision(8, 8, 0, 0, 1e-3)).toEqual({ lowPrecision: false, flagged: 0 });
+    expect(checkPrecision(8, 8, 0, 0, 1e-3, new Uint8Array(10))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2m: Interval escape tests recover exact counts
+  test('Property 2m: setCheckInterval leaves calculatePoint results unchanged', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.double({ min: -2.5, max: 1.5, noNaN: true }), // real component
+          fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+          fc.integer({ min: 1, max: 500 }),                // max_iterations
+          fc.integer({ min: 1, max: 64 }),                 // check interval
+          fc.constantFrom(0.5, 2.0, 100.0),                // escape radius
+          (real, imag, maxIterations, interval, escapeRadius) => {
+            setCheckInterval(1);
+            const expected = calculatePoint(real, imag, maxIterations, escapeRadius);
+            const expectedSmooth = calculatePoint(real, imag, maxIterations, escapeRadius, true);
+            const expectedJulia = calculateJuliaPoint(real, imag, -0.8, 0.156, maxIterations, escapeRadius);
+
+            expect(setCheckInterval(interval)).toBe(true);
+            expect(calculatePoint(real, imag, maxIterations, escapeRadius)).toBe(expected);
+            expect(calculatePoint(real, imag, maxIterations, escapeRadius, true)).toBe(expectedSmooth);
+            expect(calculateJuliaPoint(real, imag, -0.8, 0.156, maxIterations, escapeRadius)).toBe(expectedJulia);
+
+            // A checkInterval argument applies to one call
+            setCheckInterval(1);
+            expect(calculatePoint(real, imag, maxIterations, escapeRadius, 0, 0, true, null, interval)).toBe(expectedSmooth);
+            const batch = calculateMandelbrotSet([real, imag], [imag, real], maxIterations, escapeRadius, false, null, interval);
+            expect(batch).toEqual(calculateMandelbrotSet([real, imag], [imag, real], maxIterations, escapeRadius));
+            const resultBuf = new Uint32Array(2);
+            calculateMandelbrotSetTyped([real, imag], [imag, real], resultBuf, maxIterations, escapeRadius, null, interval);
+            expect(Array.from(resultBuf)).toEqual(batch);
+            expect(Array.from(new Uint32Array(calculateMandelbrotSetBuffer([real, imag], [imag, real], maxIterations, escapeRadius, null, interval)))).toEqual(batch);
+            expect(calculateMandelbrotInterleaved([real, imag, imag, real], maxIterations, escapeRadius, undefined, interval)).toEqual(batch);
+          }
+        ),
+        { numRuns: 100 }
+      );
+    } finally {
+      setCheckInterval(1);
+    }
+
+    expect(setCheckInterval(0)).toHaveProperty('error');
+    expect(calculatePoint(0.3, 0, 100, 2.0, 0, 0, false, null, 0)).toHaveProperty('error');
+    expect(calculatePoint(0.3, 0, 100, 2.0, 0, 0, false, null, '4')).toHaveProperty('error');
+    expect(calculateMandelbrotSet([0.3], [0], 100, 2.0, false, null, -1)).toHaveProperty('error');
+    expect(calculateMandelbrotInterleaved([0.3, 0], 100, 2.0, 'circle', 8)).toEqual([calculatePoint(0.3, 0, 100, 2.0)]);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 6c: Capabilities describe the exported API
+  test('Property 6c: getCapabilities lists every exported function and the accepted modes', () => {
+    const capabilities = getCapabilities();
+    expect(capabilities.version).toMatch(/^\d+\.\d+\.\d+$/);
+    expect(typeof capabilities.simd).toBe('boolean');
+    expect(capabilities.highPrecision).toBe(true);
+
+    for (const name of ['calculatePoint', 'renderViewport', 'renderRGBA', 'setBailoutShape', 'getCapabilities', 'shutdown']) {
+      expect(capabilities.functions).toContain(name);
+    }
+    expect(new Set(capabilities.functions).size).toBe(capabilities.functions.length);
+
+    for (const mode of capabilities.coloringModes) {
+      expect(setColoringMode(mode)).toBe(true);
+    }
+    setColoringMode('linear');
+    for (const shape of capabilities.bailoutShapes) {
+      expect(setBailoutShape(shape)).toBe(true);
+    }
+    setBailoutShape('circle');
+    for (const pattern of capabilities.supersamplePatterns) {
+      expect(renderRGBA(2, 2, 0, 0, 0.1, 50, 2.0, new Uint8ClampedArray(16), 2, pattern)).toBe(4);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3i: Lemniscate levels are the magnitude after exactly n steps
+  test('Property 3i: calculateLemniscateLevel returns |z_n| and its level set matches escape times', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1.5, noNaN: true }), // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 0, max: 12 }),                 // n
+        (real, imag, n) => {
+          let zReal = 0, zImag = 0;
+          for (let i = 0; i < n; i++) {
+            [zReal, zImag] = [zReal * zReal - zImag * zImag + real, 2 * zReal * zImag + imag];
+          }
+          const level = calculateLemniscateLevel(real, imag, n, 2.0);
+          if (Number.isFinite(zReal) && Number.isFinite(zImag)) {
+            expect(level).toBeCloseTo(Math.hypot(zReal, zImag), 6);
+          } else {
+            expect(level).toBe(Infinity);
+          }
+
+          // Escaping by step n puts a point outside the n-th lemniscate
+          if (n >= 1 && calculatePoint(real, imag, n, 2.0) < n) {
+            expect(level).toBeGreaterThan(2.0);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(calculateLemniscateLevel(1, 0, 0, 2.0)).toBe(0);
+    expect(calculateLemniscateLevel(1, 0, 3, 2.0)).toBe(5);
+    expect(calculateLemniscateLevel(3, 0, 5000, 2.0)).toBe(Infinity);
+    expect(calculateLemniscateLevel(0, 0, -1, 2.0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ag: Symmetric renders mirror the real axis exactly
+  test('Property 4ag: renderViewport with symmetric matches the full render', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),              // width
+        fc.integer({ min: 1, max: 24 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.constantFrom(0, 0, 0.3),                   // center imag, mostly on the axis
+        fc.double({ min: 0.001, max: 0.3, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 300 }),             // max_iterations
+        fc.boolean(),                                 // column-major
+        fc.constantFrom(1, 0.5, 2),                   // aspect
+        (width, height, centerReal, centerImag, scale, maxIterations, columnMajor, aspect) => {
+          const expected = new Uint32Array(width * height);
+          const mirrored = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected, columnMajor, 4, aspect);
+          expect(renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, mirrored, columnMajor, 4, aspect, true)).toBe(width * height);
+          expect(Array.from(mirrored)).toEqual(Array.from(expected));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // High-precision and fixed-point orbits are symmetric as well
+    for (const setMode of [setHighPrecision, setFixedPoint]) {
+      try {
+        setMode(true);
+        const expected = new Uint32Array(33 * 20);
+        const mirrored = new Uint32Array(33 * 20);
+        renderViewport(33, 20, -0.75, 0, 0.1, 200, 2.0, expected);
+        renderViewport(33, 20, -0.75, 0, 0.1, 200, 2.0, mirrored, false, 4, 1, true);
+        expect(Array.from(mirrored)).toEqual(Array.from(expected));
+      } finally {
+        setMode(false);
+      }
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ah: Progress callbacks report completed rows
+  test('Property 4ah: renderViewport calls onProgress every progressRows rows up to 1 and can be cancelled from it', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),              // width
+        fc.integer({ min: 1, max: 40 }),              // height
+        fc.integer({ min: 1, max: 20 }),              // progress rows
+        fc.boolean(),                                 // column-major
+        (width, height, progressRows, columnMajor) => {
+          const expected = new Uint32Array(width * height);
+          renderViewport(width, height, -0.5, 0.1, 0.1, 100, 2.0, expected, columnMajor);
+
+          const fractions = [];
+          const resultBuf = new Uint32Array(width * height);
+          const written = renderViewport(width, height, -0.5, 0.1, 0.1, 100, 2.0, resultBuf, columnMajor, 4, 1, false,
+            (fraction) => fractions.push(fraction), progressRows);
+          expect(written).toBe(width * height);
+          expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+
+          const lines = columnMajor ? width : height;
+          expect(fractions.length).toBe(Math.ceil(lines / progressRows));
+          fractions.forEach((fraction, i) => {
+            expect(fraction).toBe(Math.min(1, ((i + 1) * progressRows) / lines));
+          });
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Cancelling from the callback stops the render early
+    const resultBuf = new Uint32Array(64 * 64);
+    const result = renderViewport(64, 64, -0.5, 0, 0.05, 100, 2.0, resultBuf, false, 4, 1, false, () => cancelRender(), 4);
+    expect(result.cancelled).toBe(true);
+    expect(result.written).toBeLessThan(64 * 64);
+
+    expect(renderViewport(4, 4, 0, 0, 0.1, 100, 2.0, resultBuf, false, 4, 1, false, 'nope')).toHaveProperty('error');
+    expect(renderViewport(4, 4, 0, 0, 0.1, 100, 2.0, resultBuf, false, 4, 1, false, () => {}, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ai: Continued renders equal a single longer render
+  test('Property 4ai: renderViewportState followed by continueRender matches renderViewport with the total maxIterations', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.3, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 200 }),             // initial max_iterations
+        fc.array(fc.integer({ min: 1, max: 200 }), { minLength: 1, maxLength: 3 }), // continuations
+        (width, height, centerReal, centerImag, scale, maxIterations, continuations) => {
+          const pixels = width * height;
+          const stateBuf = new Float64Array(5 * pixels);
+          const resultBuf = new Uint32Array(pixels);
+          const expected = new Uint32Array(pixels);
+
+          expect(renderViewportState(width, height, centerReal, centerImag, scale, maxIterations, 2.0, stateBuf, resultBuf)).toBe(pixels);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected);
+          expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+
+          let total = maxIterations;
+          for (const extra of continuations) {
+            total += extra;
+            expect(continueRender(stateBuf, extra, 2.0, resultBuf)).toBe(pixels);
+            renderViewport(width, height, centerReal, centerImag, scale, total, 2.0, expected);
+            expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(continueRender(new Float64Array(10), 0, 2.0, new Uint32Array(2))).toHaveProperty('error');
+    expect(continueRender(new Float64Array(10), 10, 2.0, new Uint32Array(1))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4aj: Rotated viewports sample the rotated coordinates
+  test('Property 4aj: renderViewport with rotation matches calculatePoint at each rotated pixel coordinate', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),              // width
+        fc.integer({ min: 1, max: 16 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.3, noNaN: true }), // scale
+        fc.double({ min: -7, max: 7, noNaN: true }),  // rotation
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, rotation, maxIterations) => {
+          const resultBuf = new Uint32Array(width * height);
+          const written = renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, resultBuf,
+            false, 4, 1, true, null, undefined, rotation);
+          expect(written).toBe(width * height);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const dx = (x - width / 2) * scale;
+              const dy = -(y - height / 2) * scale;
+              const cReal = centerReal + Math.cos(rotation) * dx - Math.sin(rotation) * dy;
+              const cImag = centerImag + Math.sin(rotation) * dx + Math.cos(rotation) * dy;
+              expect(resultBuf[y * width + x]).toBe(calculatePoint(cReal, cImag, maxIterations, 2.0));
+            }
+          }
+
+          // A rotation of 0 is the unrotated view
+          const unrotated = new Uint32Array(width * height);
+          const zero = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, unrotated);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, zero, false, 4, 1, false, null, undefined, 0);
+          expect(Array.from(zero)).toEqual(Array.from(unrotated));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(renderViewport(4, 4, 0, 0, 0.1, 10, 2.0, new Uint32Array(16), false, 4, 1, false, null, undefined, 'x')).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ak: Jittered renders are reproducible from their seed
+  test('Property 4ak: setSeed makes jittered renders repeatable across worker counts, and setJitter(false) restores exact sampling', () => {
+    const width = 32, height = 24, pixels = width * height;
+    const render = () => {
+      const resultBuf = new Uint32Array(pixels);
+      expect(renderViewport(width, height, -0.75, 0.1, 0.01, 200, 2.0, resultBuf)).toBe(pixels);
+      return Array.from(resultBuf);
+    };
+    const plain = render();
+
+    try {
+      expect(setJitter(true)).toBe(true);
+      fc.assert(
+        fc.property(fc.integer({ min: -1000, max: 1000 }), (seed) => {
+          expect(setSeed(seed)).toBe(true);
+          const first = render();
+          const second = render();
+
+          setSeed(seed);
+          setWorkerCount(1);
+          expect(render()).toEqual(first);
+          setWorkerCount(4);
+          expect(render()).toEqual(second);
+
+          // The view crosses the boundary, so moving samples changes counts
+          expect(first).not.toEqual(plain);
+          expect(first).not.toEqual(second);
+        }),
+        { numRuns: 10 }
+      );
+    } finally {
+      setJitter(false);
+      setSeed(1);
+      setWorkerCount(1);
+    }
+
+    expect(render()).toEqual(plain);
+    expect(setSeed('x')).toHaveProperty('error');
+    expect(setJitter()).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4al: Monte Carlo area estimates
+  test('Property 4al: estimateArea is repeatable for a seed and close to the known area', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.integer({ min: -1000, max: 1000 }),    // seed
+          fc.integer({ min: 1, max: 2000 }),        // samples
+          fc.integer({ min: 1, max: 200 }),         // max_iterations
+          (seed, samples, maxIterations) => {
+            setSeed(seed);
+            const first = estimateArea(samples, maxIterations);
+            setSeed(seed);
+            expect(estimateArea(samples, maxIterations)).toEqual(first);
+
+            // The area is the member fraction of a 5.75 box
+            const members = first.area / 5.75 * samples;
+            expect(Math.abs(members - Math.round(members))).toBeLessThan(1e-6);
+            const fraction = first.area / 5.75;
+            expect(first.standardError).toBeCloseTo(5.75 * Math.sqrt(fraction * (1 - fraction) / samples), 12);
+          }
+        ),
+        { numRuns: 50 }
+      );
+
+      setSeed(7);
+      const estimate = estimateArea(200000, 1000);
+      expect(Math.abs(estimate.area - 1.5066)).toBeLessThan(Math.max(5 * estimate.standardError, 0.02));
+      expect(estimate.standardError).toBeGreaterThan(0);
+      expect(estimate.standardError).toBeLessThan(0.01);
+    } finally {
+      setSeed(1);
+    }
+
+    expect(estimateArea(0, 100)).toHaveProperty('error');
+    expect(estimateArea(100)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2n: Non-finite coordinates escape immediately
+  test('Property 2n: NaN and infinite coordinates return 0 iterations from calculatePoint and the batch functions', () => {
+    const nonFinite = fc.constantFrom(NaN, Infinity, -Infinity);
+    fc.assert(
+      fc.property(
+        nonFinite,
+        fc.double({ min: -2, max: 2, noNaN: true }),  // finite component
+        fc.boolean(),                                 // which component is non-finite
+        fc.integer({ min: 1, max: 1000 }),            // max_iterations
+        (bad, finite, badReal, maxIterations) => {
+          const real = badReal ? bad : finite;
+          const imag = badReal ? finite : bad;
+
+          expect(calculatePoint(real, imag, maxIterations, 2.0)).toBe(0);
+          expect(calculatePoint(real, imag, maxIterations, 2.0, true)).toBe(0);
+          expect(calculatePoint(0, 0, maxIterations, 2.0, real, imag)).toBe(0);
+          expect(isInSet(real, imag, maxIterations)).toBe(false);
+
+          const realCoords = [real, 0.3, -0.75];
+          const imagCoords = [imag, 0.5, 0.1];
+          const expected = [0, calculatePoint(0.3, 0.5, maxIterations, 2.0), calculatePoint(-0.75, 0.1, maxIterations, 2.0)];
+          expect(calculateMandelbrotSet(realCoords, imagCoords, maxIterations, 2.0)).toEqual(expected);
+
+          const resultBuf = new Uint32Array(3);
+          calculateMandelbrotSetTyped(new Float64Array(realCoords), new Float64Array(imagCoords), resultBuf, maxIterations, 2.0);
+          expect(Array.from(resultBuf)).toEqual(expected);
+
+          // The orbit-walking functions escape at once too, instead of passing for interior
+          const withPhase = calculatePointWithPhase(real, imag, maxIterations, 2.0);
+          expect(withPhase.iterations).toBe(0);
+          expect(withPhase.phase).toBeNaN();
+          expect(calculateStripePoint(real, imag, maxIterations, 2.0, 3)).toEqual({ iterations: 0, smooth: 0, stripe: 0 });
+          expect(calculateOrbitTrapPoint(real, imag, maxIterations, 2.0, 0, 0)).toEqual({ iterations: 0, distance: Infinity });
+          expect(calculateDistanceEstimate(real, imag, maxIterations, 2.0)).toBe(Infinity);
+          expect(calculateDistanceEstimate(real, imag, maxIterations, 2.0, 0.01)).toBe(1);
+          expect(calculateLinearEscapeTime(real, imag, maxIterations, 2.0)).toBe(0);
+          expect(calculateOrbit(real, imag, maxIterations, 2.0, 10)).toEqual([]);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    try {
+      setStructuredResults(true);
+      expect(calculatePoint(NaN, 0, 100, 2.0)).toEqual({ escaped: true, iterations: 0, smooth: 0 });
+    } finally {
+      setStructuredResults(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 8b: Halley's method reports a root it actually reached, faster than Newton
+  test('Property 8b: calculateHalleyPoint converges to the cube roots of unity in no more steps than Newton near them', () => {
+    const roots = [[1, 0], [-0.5, Math.sqrt(3) / 2], [-0.5, -Math.sqrt(3) / 2]];
+
+    fc.assert(
+      fc.property(
+        fc.double({ min: -3, max: 3, noNaN: true }),  // real component
+        fc.double({ min: -3, max: 3, noNaN: true }),  // imaginary component
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        (real, imag, maxIterations) => {
+          const { iterations, root } = calculateHalleyPoint(real, imag, maxIterations, 1e-6);
+
+          expect(iterations).toBeLessThanOrEqual(maxIterations);
+          if (root === -1) {
+            expect(iterations).toBe(maxIterations);
+          } else {
+            expect([0, 1, 2]).toContain(root);
+            expect(iterations).toBeLessThan(maxIterations);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Near a root both methods find it, Halley in no more steps
+    roots.forEach(([real, imag], index) => {
+      expect(calculateHalleyPoint(real, imag, 50, 1e-6)).toEqual({ iterations: 0, root: index });
+      const halley = calculateHalleyPoint(real * 1.3, imag * 1.3, 50, 1e-9);
+      const newton = calculateNewtonPoint(real * 1.3, imag * 1.3, 50, 1e-9);
+      expect(halley.root).toBe(index);
+      expect(newton.root).toBe(index);
+      expect(halley.iterations).toBeLessThan(newton.iterations);
+    });
+
+    // z = 0 is a fixed point of Halley's step
+    expect(calculateHalleyPoint(0, 0, 50, 1e-6)).toEqual({ iterations: 50, root: -1 });
+    expect(calculateHalleyPoint(0.5, 0, 50, 0)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3j: The continuous potential
+  test('Property 3j: calculatePotential is log|z_n| / 2^n for escaped points and 0 for interior points', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2.5, max: 1.5, noNaN: true }), // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 500 }),                // max_iterations
+        fc.constantFrom(2, 10, 1000),                    // escape radius
+        (real, imag, maxIterations, escapeRadius) => {
+          const potential = calculatePotential(real, imag, maxIterations, escapeRadius);
+          const { iterations, magnitudeSquared } = calculatePointWithMagnitude(real, imag, maxIterations, escapeRadius);
+
+          if (iterations >= maxIterations) {
+            expect(potential).toBe(0);
+          } else {
+            expect(potential).toBeGreaterThan(0);
+            expect(potential).toBeCloseTo(Math.log(magnitudeSquared) / 2 / Math.pow(2, iterations), 12);
+          }
+        }
+      ),
+      { numRuns: 200 }
+    );
+
+    // With a large radius the potential approaches log|c| / 2 far from the set
+    expect(calculatePotential(100, 0, 100, 1e10)).toBeCloseTo(Math.log(100) / 2, 2);
+    expect(calculatePotential(-0.5, 0, 100, 2)).toBe(0);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4am: Fused grid computation
+  test('Property 4am: computeGrid returns a Uint32Array equal to renderViewport of the same view', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 32 }),              // width
+        fc.integer({ min: 1, max: 32 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.0001, max: 0.2, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 300 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const counts = computeGrid(width, height, centerReal, centerImag, scale, maxIterations, 2.0);
+          const expected = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, expected);
+
+          expect(counts).toBeInstanceOf(Uint32Array);
+          expect(counts.cancelled).toBeUndefined();
+          expect(Array.from(counts)).toEqual(Array.from(expected));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(computeGrid(0, 4, 0, 0, 0.1, 10, 2.0)).toHaveProperty('error');
+    expect(computeGrid(4, 4, 0, 0, 0.1, 10)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5k: Proven interior points report the interior value
+  test('Property 5k: setInteriorValue replaces maxIterations only for points proven interior', () => {
+    const interior = 0xFFFFFFFF;
+    try {
+      fc.assert(
+        fc.property(
+          fc.double({ min: -2, max: 0.5, noNaN: true }),  // real component
+          fc.double({ min: -1.2, max: 1.2, noNaN: true }), // imaginary component
+          fc.integer({ min: 1, max: 300 }),                // max_iterations
+          fc.boolean(),                                    // periodicity checking
+          (real, imag, maxIterations, periodic) => {
+            setPeriodicityCheck(periodic);
+            setInteriorValue();
+            const plain = calculatePoint(real, imag, maxIterations, 2.0);
+            expect(setInteriorValue(interior)).toBe(true);
+            const reported = calculatePoint(real, imag, maxIterations, 2.0);
+
+            if (reported !== plain) {
+              expect(plain).toBe(maxIterations);
+              expect(reported).toBe(interior);
+            }
+            expect(calculateMandelbrotSet([real], [imag], maxIterations, 2.0)).toEqual([reported]);
+            // Pixel (1, 1) of a 2x2 view samples its center exactly
+            const viewBuf = new Uint32Array(4);
+            renderViewport(2, 2, real, imag, 0.01, maxIterations, 2.0, viewBuf);
+            expect(viewBuf[3]).toBe(reported);
+          }
+        ),
+        { numRuns: 200 }
+      );
+
+      setPeriodicityCheck(false);
+      setInteriorValue(interior);
+      // In the main cardioid: proven
+      expect(calculatePoint(-0.1, 0, 100, 2.0)).toBe(interior);
+      expect(calculatePoint(-0.1, 0, 100, 2.0, true)).toBe(interior);
+      // Escapes after more than 10 iterations: exhausted, not proven
+      expect(calculatePoint(0.26, 0, 10, 2.0)).toBe(10);
+      // A period-3 bulb point is only proven with periodicity checking
+      expect(calculatePoint(-0.12, 0.75, 500, 2.0)).toBe(500);
+      setPeriodicityCheck(true);
+      expect(calculatePoint(-0.12, 0.75, 500, 2.0)).toBe(interior);
+
+      // The interior mask and escape range still treat proven points as
+      // interior whatever value they report, including values below maxIterations
+      setPeriodicityCheck(false);
+      for (const value of [0, 1, 50, 99, 100, 101, interior]) {
+        expect(setInteriorValue(value)).toBe(true);
+        const ranged = calculateMandelbrotSet([0, 0.25, 2, -0.75, 1], [0, 0, 0, 0.1, 1], 100, 2, true);
+        expect(ranged.results[0]).toBe(value);
+        expect(ranged.min).toBe(calculatePoint(2, 0, 100, 2));
+        expect(ranged.max).toBe(Math.max(calculatePoint(-0.75, 0.1, 100, 2), calculatePoint(1, 1, 100, 2)));
+
+        // An all-interior 4x4 view in the main cardioid
+        const offset = getMemoryBuffer(64 + 2);
+        expect(renderToMemory(offset, 4, 4, -0.2, 0, 0.01, 100, 2.0, false, 4, offset + 64)).toBe(16);
+        expect(Array.from(new Uint8Array(wasmMemory.buffer, offset + 64, 2))).toEqual([255, 255]);
+        expect(Array.from(new Uint32Array(wasmMemory.buffer, offset, 16))).toEqual(new Array(16).fill(value));
+        expect(renderToMemory(offset, 4, 4, -0.2, 0, 0.01, 100, 2.0, false, 1, offset + 64)).toBe(16);
+        expect(Array.from(new Uint8Array(wasmMemory.buffer, offset + 64, 2))).toEqual([255, 255]);
+        expect(Array.from(new Uint8Array(wasmMemory.buffer, offset, 16))).toEqual(new Array(16).fill(Math.min(value, 255)));
+      }
+      // The interior value is back in place afterwards
+      expect(calculatePoint(-0.1, 0, 100, 2.0)).toBe(interior);
+    } finally {
+      setInteriorValue(null);
+      setPeriodicityCheck(false);
+    }
+
+    expect(calculatePoint(-0.1, 0, 100, 2.0)).toBe(100);
+    expect(setInteriorValue(-1)).toHaveProperty('error');
+    expect(setInteriorValue(1.5)).toHaveProperty('error');
+    expect(setInteriorValue(1, 2)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4an: Float texture channels
+  test('Property 4an: renderFloatTexture packs the normalized smooth count, angle, magnitude and interior flag of every pixel', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),              // width
+        fc.integer({ min: 1, max: 12 }),              // height
+        fc.double({ min: -2, max: 1, noNaN: true }),  // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.double({ min: 0.001, max: 0.3, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 300 }),             // max_iterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          const pixels = width * height;
+          const floatBuf = new Float32Array(4 * pixels);
+          expect(renderFloatTexture(width, height, centerReal, centerImag, scale, maxIterations, 2.0, floatBuf)).toBe(pixels);
+
+          const realBuf = new Float64Array(pixels);
+          const imagBuf = new Float64Array(pixels);
+          generateCoordinates(width, height, centerReal, centerImag, scale, realBuf, imagBuf);
+
+          for (let i = 0; i < pixels; i++) {
+            const texel = Array.from(floatBuf.subarray(4 * i, 4 * i + 4));
+            const iterations = calculatePoint(realBuf[i], imagBuf[i], maxIterations, 2.0);
+            if (iterations >= maxIterations) {
+              expect(texel).toEqual([1, 0, 0, 1]);
+              continue;
+            }
+            const smooth = calculatePoint(realBuf[i], imagBuf[i], maxIterations, 2.0, true);
+            const { phase } = calculatePointWithPhase(realBuf[i], imagBuf[i], maxIterations, 2.0);
+            const { magnitudeSquared } = calculatePointWithMagnitude(realBuf[i], imagBuf[i], maxIterations, 2.0);
+            expect(texel).toEqual([
+              Math.fround(Math.min(Math.max(smooth / maxIterations, 0), 1)),
+              Math.fround(phase),
+              Math.fround(Math.sqrt(magnitudeSquared)),
+              0,
+            ]);
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(renderFloatTexture(2, 2, 0, 0, 0.1, 10, 2.0, new Float32Array(15))).toHaveProperty('error');
+    expect(renderFloatTexture(2, 2, 0, 0, 0.1, 10, 2.0, new Float64Array(16))).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 3k: Line and cross traps match distances over the orbit
+  test('Property 3k: Line and cross traps report the minimum distance over the orbit', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2, max: 1, noNaN: true }),  // real component
+        fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+        fc.integer({ min: 1, max: 200 }),             // max_iterations
+        fc.double({ min: 0.1, max: 2, noNaN: true }), // a
+        fc.double({ min: -2, max: 2, noNaN: true }),  // b
+        fc.double({ min: -2, max: 2, noNaN: true }),  // c
+        (real, imag, maxIterations, a, b, c) => {
+          const orbit = calculateOrbit(real, imag, maxIterations, 2.0, maxIterations);
+          let lineMin = Infinity;
+          let crossMin = Infinity;
+          for (let i = 0; i < orbit.length; i += 2) {
+            lineMin = Math.min(lineMin, Math.abs(a * orbit[i] + b * orbit[i + 1] + c) / Math.hypot(a, b));
+            crossMin = Math.min(crossMin, Math.abs(orbit[i]), Math.abs(orbit[i + 1]));
+          }
+
+          const line = calculateOrbitTrapLine(real, imag, maxIterations, 2.0, a, b, c);
+          expect(line.iterations).toBe(calculatePoint(real, imag, maxIterations, 2.0));
+          expect(line.distance).toBeCloseTo(lineMin, 9);
+
+          const cross = calculateOrbitTrapCross(real, imag, maxIterations, 2.0);
+          expect(cross.iterations).toBe(line.iterations);
+          expect(cross.distance).toBe(crossMin);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(calculateOrbitTrapLine(0, 0, 100, 2.0, 0, 0, 1)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 2o: Strict lengths reject mismatched coordinate arrays
+  test('Property 2o: setStrictLengths turns truncation of mismatched arrays into an error', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.array(fc.double({ min: -2, max: 2, noNaN: true }), { minLength: 1, maxLength: 20 }),
+          fc.array(fc.double({ min: -2, max: 2, noNaN: true }), { minLength: 1, maxLength: 20 }),
+          (realCoords, imagCoords) => {
+            const shorter = Math.min(realCoords.length, imagCoords.length);
+
+            expect(setStrictLengths(false)).toBe(true);
+            const lenient = calculateMandelbrotSet(realCoords, imagCoords, 100, 2.0);
+            expect(lenient.length).toBe(shorter);
+
+            expect(setStrictLengths(true)).toBe(true);
+            const strict = calculateMandelbrotSet(realCoords, imagCoords, 100, 2.0);
+            const strictTyped = calculateMandelbrotSetTyped(
+              new Float64Array(realCoords), new Float64Array(imagCoords), new Uint32Array(20), 100, 2.0);
+            const strictJulia = calculateJuliaSet(realCoords, imagCoords, -0.8, 0.156, 100, 2.0);
+            if (realCoords.length === imagCoords.length) {
+              expect(strict).toEqual(lenient);
+              expect(strictTyped).toBe(shorter);
+              expect(strictJulia.length).toBe(shorter);
+            } else {
+              expect(strict.error).toContain('realCoords and imagCoords must have the same length');
+              expect(strictTyped.error).toContain('realBuf and imagBuf');
+              expect(strictJulia).toHaveProperty('error');
+            }
+          }
+        ),
+        { numRuns: 100 }
+      );
+    } finally {
+      setStrictLengths(false);
+    }
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 8c: Logistic map escape time matches a direct iteration
+  test('Property 8c: calculateLogistic matches iterating x = r*x*(1-x)', () => {
+    fc.assert(
+      fc.property(
+        fc.double({ min: 0, max: 6, noNaN: true }),   // r
+        fc.double({ min: -1, max: 2, noNaN: true }),  // x0
+        fc.integer({ min: 1, max: 500 }),             // max_iterations
+        (r, x0, maxIterations) => {
+          let x = x0;
+          let expected = maxIterations;
+          for (let i = 0; i < maxIterations; i++) {
+            if (Math.abs(x) > 10) {
+              expected = i;
+              break;
+            }
+            x = r * x * (1 - x);
+          }
+          expect(calculateLogistic(r, x0, maxIterations, 10)).toBe(expected);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // The unit interval is invariant for r up to 4
+    expect(calculateLogistic(4, 0.3, 1000, 10)).toBe(1000);
+    expect(calculateLogistic(NaN, 0.3, 1000, 10)).toBe(0);
+    expect(calculateLogistic(3, 0.3, 1000, 0)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 5l: Palette lookup tables approximate the direct palette
+  test('Property 5l: setPalette lookup tables match direct evaluation closely', () => {
+    const width = 24;
+    const height = 16;
+    const render = () => {
+      const pixels = new Uint8ClampedArray(width * height * 4);
+      expect(renderRGBA(width, height, -0.5, 0, 3 / width, 64, 2.0, pixels)).toBe(width * height);
+      return pixels;
+    };
+
+    try {
+      expect(setPalette('rainbow')).toBe(true);
+      const direct = render();
+
+      expect(setPalette('rainbow', 65536, true)).toBe(true);
+      const fine = render();
+      for (let i = 0; i < direct.length; i++) {
+        expect(Math.abs(fine[i] - direct[i])).toBeLessThanOrEqual(1);
+      }
+
+      // Two nearest-entry samples of grayscale are pure black and white
+      expect(setPalette('grayscale', 2)).toBe(true);
+      const coarse = render();
+      for (let i = 0; i < coarse.length; i++) {
+        expect([0, 255]).toContain(coarse[i]);
+      }
+
+      expect(setPalette('grayscale', 1)).toHaveProperty('error');
+      expect(setPalette('grayscale', 65537)).toHaveProperty('error');
+    } finally {
+      setPalette('rainbow');
+    }
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 5n: Each named palette colors renderRGBA differently
+  test('Property 5n: setPalette selects the palette renderRGBA uses and rejects unknown names', () => {
+    const width = 24;
+    const height = 16;
+    const render = () => {
+      const pixels = new Uint8ClampedArray(width * height * 4);
+      expect(renderRGBA(width, height, -0.5, 0, 3 / width, 64, 2.0, pixels)).toBe(width * height);
+      return Array.from(pixels);
+    };
+
+    try {
+      const renders = {};
+      for (const name of ['grayscale', 'fire', 'ocean', 'rainbow']) {
+        expect(setPalette(name)).toBe(true);
+        renders[name] = render();
+        expect(render()).toEqual(renders[name]);
+      }
+
+      // Every pair of palettes differs somewhere, and interior pixels stay black in all of them
+      const names = Object.keys(renders);
+      for (let i = 0; i < names.length; i++) {
+        for (let j = i + 1; j < names.length; j++) {
+          expect(renders[names[i]]).not.toEqual(renders[names[j]]);
+        }
+      }
+      const center = 4 * ((height / 2) * width + width / 2);
+      for (const name of names) {
+        expect(renders[name].slice(center, center + 4)).toEqual([0, 0, 0, 255]);
+      }
+      // grayscale has equal channels everywhere
+      for (let i = 0; i < renders.grayscale.length; i += 4) {
+        expect(renders.grayscale[i + 1]).toBe(renders.grayscale[i]);
+        expect(renders.grayscale[i + 2]).toBe(renders.grayscale[i]);
+      }
+
+      // An unknown name is an error and keeps the selected palette
+      expect(setPalette('fire')).toBe(true);
+      for (const bad of ['sepia', '', 'Fire']) {
+        const result = setPalette(bad);
+        expect(result).toHaveProperty('error');
+        expect(result.error).toContain('unknown palette');
+      }
+      expect(setPalette(1)).toHaveProperty('error');
+      expect(setPalette()).toHaveProperty('error');
+      expect(render()).toEqual(renders.fire);
+    } finally {
+      setPalette('rainbow');
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 6d: Recommended iterations grow with zoom depth
+  test('Property 6d: recommendedIterations follows base + perDoubling * log2(referenceScale / scale)', () => {
+    const referenceScale = 3.5 / 800;
+    fc.assert(
+      fc.property(
+        fc.double({ min: -60, max: 10, noNaN: true }), // log2 of scale relative to the reference
+        fc.integer({ min: 1, max: 5000 }),             // base
+        fc.double({ min: 0, max: 200, noNaN: true }),  // perDoubling
+        (exponent, base, perDoubling) => {
+          const scale = referenceScale * Math.pow(2, exponent);
+          const expected = Math.round(base + perDoubling * Math.max(0, -exponent));
+          expect(Math.abs(recommendedIterations(scale, base, perDoubling) - expected)).toBeLessThanOrEqual(1);
+
+          // A deeper zoom never suggests fewer iterations
+          expect(recommendedIterations(scale / 2, base, perDoubling)).toBeGreaterThanOrEqual(
+            recommendedIterations(scale, base, perDoubling));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(recommendedIterations(referenceScale / Math.pow(2, 20))).toBe(256 + 32 * 20);
+    expect(recommendedIterations(0)).toHaveProperty('error');
+    expect(recommendedIterations(1e-3, 100, -1)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4ao: diffRegion bounds exactly the changed pixels
+  test('Property 4ao: diffRegion returns the bounding rectangle of changed pixels', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),  // width
+        fc.integer({ min: 1, max: 24 }),  // height
+        fc.array(fc.nat(), { maxLength: 6 }), // changed byte positions
+        fc.constantFrom(1, 4),            // bytes per pixel
+        (width, height, changes, bytesPerPixel) => {
+          const oldBuf = new Uint8Array(width * height * bytesPerPixel);
+          for (let i = 0; i < oldBuf.length; i++) oldBuf[i] = (i * 37) & 255;
+          const newBuf = oldBuf.slice();
+
+          let left = Infinity, top = Infinity, right = -Infinity, bottom = -Infinity;
+          for (const position of changes) {
+            // Derived from oldBuf so repeated positions still differ
+            const byte = position % newBuf.length;
+            newBuf[byte] = oldBuf[byte] ^ 0x80;
+            const pixel = Math.floor(byte / bytesPerPixel);
+            const x = pixel % width;
+            const y = Math.floor(pixel / width);
+            left = Math.min(left, x);
+            right = Math.max(right, x + 1);
+            top = Math.min(top, y);
+            bottom = Math.max(bottom, y + 1);
+          }
+
+          const dirty = diffRegion(oldBuf, newBuf, width, height);
+          if (changes.length === 0) {
+            expect(dirty).toBeNull();
+          } else {
+            expect(dirty).toEqual({ x: left, y: top, width: right - left, height: bottom - top });
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Counts compare per Uint32 pixel
+    const counts = new Uint32Array(16);
+    const changed = counts.slice();
+    changed[6] = 1000;
+    expect(diffRegion(counts, changed, 4, 4)).toEqual({ x: 2, y: 1, width: 1, height: 1 });
+    expect(diffRegion(counts, new Uint32Array(8), 4, 4)).toHaveProperty('error');
+    expect(diffRegion(counts, changed, 3, 3)).toHaveProperty('error');
+    expect(diffRegion([0], [0], 1, 1)).toHaveProperty('error');
+    // 2^32 x 2^32 would wrap width*height to 0 in an int
+    expect(diffRegion(new Uint8Array(4), new Uint8Array(4), 4294967296, 4294967296)).toHaveProperty('error');
+    expect(diffRegion(new Uint8Array(4), new Uint8Array(4), 2, 2)).toBeNull();
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4ap: Row bands tile the full viewport render
+  test('Property 4ap: renderRows bands match the rows of renderViewport', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),              // width
+        fc.integer({ min: 1, max: 24 }),              // height
+        fc.double({ min: -1.5, max: 0.5, noNaN: true }), // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),  // center imag
+        fc.integer({ min: 1, max: 7 }),               // band height
+        (width, height, centerReal, centerImag, bandHeight) => {
+          const scale = 3 / width;
+          const full = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, 100, 2.0, full);
+
+          const band = new Uint32Array(bandHeight * width);
+          for (let startRow = 0; startRow < height; startRow += bandHeight) {
+            const rowCount = Math.min(bandHeight, height - startRow);
+            expect(renderRows(startRow, rowCount, width, height, centerReal, centerImag, scale, 100, 2.0, band)).toBe(rowCount * width);
+            expect(Array.from(band.subarray(0, rowCount * width))).toEqual(
+              Array.from(full.subarray(startRow * width, (startRow + rowCount) * width)));
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    const band = new Uint32Array(16);
+    expect(renderRows(3, 2, 4, 4, 0, 0, 1, 100, 2.0, band)).toHaveProperty('error');
+    expect(renderRows(-1, 1, 4, 4, 0, 0, 1, 100, 2.0, band)).toHaveProperty('error');
+    expect(renderRows(0, 2, 16, 4, 0, 0, 1, 100, 2.0, band)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4aq: renderTile stats report the fraction at maxIterations
+  test('Property 4aq: renderTile withStats reports the fraction of pixels at maxIterations', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 0, max: 4 }),   // zoom
+        fc.nat(),                         // tile selector
+        fc.integer({ min: 1, max: 16 }),  // tile size
+        fc.integer({ min: 1, max: 200 }), // max_iterations
+        (zoom, selector, tileSize, maxIterations) => {
+          const tiles = 1 << zoom;
+          const tileX = selector % tiles;
+          const tileY = Math.floor(selector / tiles) % tiles;
+          const plain = new Uint32Array(tileSize * tileSize);
+          expect(renderTile(tileX, tileY, zoom, tileSize, maxIterations, 2.0, plain)).toBe(plain.length);
+
+          const buf = new Uint32Array(tileSize * tileSize);
+          const stats = renderTile(tileX, tileY, zoom, tileSize, maxIterations, 2.0, buf, true);
+          expect(buf).toEqual(plain);
+          const maxed = plain.filter((count) => count === maxIterations).length;
+          expect(stats).toEqual({ written: plain.length, maxIterationsFraction: maxed / plain.length });
+        }
+      ),
+      { numRuns: 100 }
+    );
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 3l: Julia keyframes match the Julia set of the interpolated parameter
+  test('Property 3l: juliaKeyframe equals calculateJuliaSet at the interpolated c', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.tuple(fc.double({ min: -2, max: 2, noNaN: true }), fc.double({ min: -2, max: 2, noNaN: true })), { minLength: 1, maxLength: 30 }),
+        fc.double({ min: -1, max: 1, noNaN: true }), // c start real
+        fc.double({ min: -1, max: 1, noNaN: true }), // c start imag
+        fc.double({ min: -1, max: 1, noNaN: true }), // c end real
+        fc.double({ min: -1, max: 1, noNaN: true }), // c end imag
+        fc.double({ min: 0, max: 1, noNaN: true }),  // t
+        (points, startReal, startImag, endReal, endImag, t) => {
+          const realCoords = points.map(([re]) => re);
+          const imagCoords = points.map(([, im]) => im);
+          const cReal = (1 - t) * startReal + t * endReal;
+          const cImag = (1 - t) * startImag + t * endImag;
+          expect(juliaKeyframe(realCoords, imagCoords, startReal, startImag, endReal, endImag, t, 100, 2.0))
+            .toEqual(calculateJuliaSet(realCoords, imagCoords, cReal, cImag, 100, 2.0));
+
+          // The endpoints are exact
+          expect(juliaKeyframe(realCoords, imagCoords, startReal, startImag, endReal, endImag, 0, 100, 2.0))
+            .toEqual(calculateJuliaSet(realCoords, imagCoords, startReal, startImag, 100, 2.0));
+          expect(juliaKeyframe(realCoords, imagCoords, startReal, startImag, endReal, endImag, 1, 100, 2.0))
+            .toEqual(calculateJuliaSet(realCoords, imagCoords, endReal, endImag, 100, 2.0));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(juliaKeyframe([0], [0], 0, 0, 1, 1, 1.5, 100, 2.0)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4ar: Allocated buffers are separate stable render targets
+  test('Property 4ar: allocBuffer regions hold renderToMemory output until freed', () => {
+    fc.assert(
+      fc.property(
+        fc.array(fc.tuple(fc.integer({ min: 1, max: 12 }), fc.integer({ min: 1, max: 12 })), { minLength: 1, maxLength: 4 }), // sizes
+        fc.integer({ min: 1, max: 300 }), // max_iterations
+        (sizes, maxIterations) => {
+          const offsets = sizes.map(([width, height]) => allocBuffer(width * height * 4));
+          try {
+            offsets.forEach((offset) => expect(offset % 8).toBe(0));
+            expect(new Set(offsets).size).toBe(offsets.length);
+
+            sizes.forEach(([width, height], i) => {
+              expect(renderToMemory(offsets[i], width, height, -0.5, 0.2 * i, 3 / width, maxIterations, 2.0)).toBe(width * height);
+            });
+
+            // Every region still holds its own render after the others were written
+            sizes.forEach(([width, height], i) => {
+              const expected = new Uint32Array(width * height);
+              renderViewport(width, height, -0.5, 0.2 * i, 3 / width, maxIterations, 2.0, expected);
+              expect(Array.from(new Uint32Array(wasmMemory.buffer, offsets[i], width * height))).toEqual(Array.from(expected));
+            });
+          } finally {
+            offsets.forEach((offset) => expect(freeBuffer(offset)).toBe(true));
+          }
+
+          // Freed regions are no longer valid targets
+          const [width, height] = sizes[0];
+          expect(renderToMemory(offsets[0], width, height, -0.5, 0, 3 / width, maxIterations, 2.0)).toHaveProperty('error');
+          expect(freeBuffer(offsets[0])).toHaveProperty('error');
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(allocBuffer(0)).toHaveProperty('error');
+    // Lengths beyond the wasm32 address space are rejected, not allocated
+    expect(allocBuffer(1e15)).toHaveProperty('error');
+    expect(allocBuffer(2 ** 32)).toHaveProperty('error');
+    expect(allocBuffer(Infinity)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 2p: Inclusive escape matches a >= reference
+  test('Property 2p: setInclusiveEscape switches the escape test to |z|^2 >= R^2', () => {
+    const reference = (real, imag, maxIterations, escapeRadius) => {
+      let zReal = 0;
+      let zImag = 0;
+      for (let iteration = 0; iteration < maxIterations; iteration++) {
+        if (zReal * zReal + zImag * zImag >= escapeRadius * escapeRadius) return iteration;
+        const next = zReal * zReal - zImag * zImag + real;
+        zImag = 2 * zReal * zImag + imag;
+        zReal = next;
+      }
+      return maxIterations;
+    };
+
+    try {
+      // Orbits landing exactly on the circle
+      expect(calculatePoint(2, 0, 100, 2.0)).toBe(2);
+      expect(calculatePoint(-2, 0, 100, 2.0)).toBe(100);
+      expect(setInclusiveEscape(true)).toBe(true);
+      expect(calculatePoint(2, 0, 100, 2.0)).toBe(1);
+      expect(calculatePoint(-2, 0, 100, 2.0)).toBe(1);
+      expect(calculateMandelbrotSet([2, -2, 0], [0, 0, 0], 100, 2.0)).toEqual([1, 1, 100]);
+
+      fc.assert(
+        fc.property(
+          fc.integer({ min: -16, max: 8 }),   // real, in quarters
+          fc.integer({ min: -8, max: 8 }),    // imag, in quarters
+          fc.integer({ min: 1, max: 200 }),   // max_iterations
+          fc.constantFrom(1, 1.5, 2, 4),      // escape radius
+          (realQuarters, imagQuarters, maxIterations, escapeRadius) => {
+            const real = realQuarters / 4;
+            const imag = imagQuarters / 4;
+            const expected = reference(real, imag, maxIterations, escapeRadius);
+            expect(calculatePoint(real, imag, maxIterations, escapeRadius)).toBe(expected);
+            expect(calculateMandelbrotSet([real], [imag], maxIterations, escapeRadius)).toEqual([expected]);
+          }
+        ),
+        { numRuns: 100 }
+      );
+    } finally {
+      setInclusiveEscape(false);
+    }
+  });
+
+  // Feature: mandelbrot-visualizer, Property 2q: Periodicity checking only changes interior counts
+  test('Property 2q: setPeriodicityCheck stops periodic orbits early and keeps escape counts', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.double({ min: -2.5, max: 1, noNaN: true }),  // real component
+          fc.double({ min: -1.5, max: 1.5, noNaN: true }), // imaginary component
+          fc.integer({ min: 1, max: 1000 }),               // max_iterations
+          (real, imag, maxIterations) => {
+            setPeriodicityCheck(false);
+            const plain = calculatePoint(real, imag, maxIterations, 2.0);
+            expect(setPeriodicityCheck(true)).toBe(true);
+            if (plain < maxIterations) {
+              expect(calculatePoint(real, imag, maxIterations, 2.0)).toBe(plain);
+              expect(calculateMandelbrotSet([real], [imag], maxIterations, 2.0)).toEqual([plain]);
+            }
+          }
+        ),
+        { numRuns: 200 }
+      );
+
+      // A period-3 bulb point, missed by the cardioid and bulb test, is caught
+      // as a cycle: with the interior value set it reports as proven
+      setInteriorValue(0);
+      setPeriodicityCheck(false);
+      expect(calculatePoint(-0.12, 0.75, 1e6, 2.0)).toBe(1e6);
+      expect(setPeriodicityCheck(true, 1e-8)).toBe(true);
+      expect(calculatePoint(-0.12, 0.75, 1e6, 2.0)).toBe(0);
+    } finally {
+      setInteriorValue(null);
+      setPeriodicityCheck(false);
+    }
+
+    for (const epsilon of [0, -1e-10, NaN, Infinity, -Infinity]) {
+      expect(setPeriodicityCheck(true, epsilon)).toHaveProperty('error');
+    }
+    expect(setPeriodicityCheck(true, '1e-10')).toHaveProperty('error');
+    expect(setPeriodicityCheck()).toHaveProperty('error');
+    // A rejected call leaves checking off, so the point isn't proven
+    setInteriorValue(0);
+    expect(calculatePoint(-0.12, 0.75, 1000, 2.0)).toBe(1000);
+    setInteriorValue(null);
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 3m: Nuclei found by the search return to 0 after their period
+  test('Property 3m: findNearbyPeriodicPoint returns roots of z_period(c) = 0', () => {
+    const orbitAt = (real, imag, steps) => {
+      let zReal = 0;
+      let zImag = 0;
+      for (let n = 0; n < steps; n++) {
+        const next = zReal * zReal - zImag * zImag + real;
+        zImag = 2 * zReal * zImag + imag;
+        zReal = next;
+      }
+      return Math.hypot(zReal, zImag);
+    };
+
+    fc.assert(
+      fc.property(
+        fc.double({ min: -2, max: 0.5, noNaN: true }),  // start real
+        fc.double({ min: -1.2, max: 1.2, noNaN: true }), // start imag
+        fc.integer({ min: 1, max: 12 }),                // max period
+        (startReal, startImag, maxPeriod) => {
+          const result = findNearbyPeriodicPoint(startReal, startImag, maxPeriod, 100);
+          if (!result.found) {
+            expect(result).toEqual({ found: false });
+            return;
+          }
+          expect(result.period).toBeGreaterThanOrEqual(1);
+          expect(result.period).toBeLessThanOrEqual(maxPeriod);
+          expect(orbitAt(result.real, result.imag, result.period)).toBeLessThan(1e-9);
+          // The nucleus is a member of the set
+          expect(isInSet(result.real, result.imag, 1000)).toBe(true);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    const rabbit = findNearbyPeriodicPoint(-0.12, 0.75, 10, 100);
+    expect(rabbit.period).toBe(3);
+    expect(rabbit.real).toBeCloseTo(-0.1225611668766536, 12);
+    expect(rabbit.imag).toBeCloseTo(0.7448617666197442, 12);
+    expect(findNearbyPeriodicPoint(-1.02, 0.01, 10, 100)).toEqual({ found: true, real: -1, imag: 0, period: 2 });
+    expect(findNearbyPeriodicPoint(5, 5, 10, 100)).toEqual({ found: false });
+    expect(findNearbyPeriodicPoint(NaN, 0, 10, 100)).toHaveProperty('error');
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4as: Scale bounds clamp renderViewport and report it
+  test('Property 4as: setBounds clamps the renderViewport scale and flags it', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.integer({ min: 1, max: 16 }),                 // width
+          fc.integer({ min: 1, max: 16 }),                 // height
+          fc.double({ min: 1e-6, max: 10, noNaN: true }),  // scale
+          fc.double({ min: 1e-4, max: 1, noNaN: true }),   // min scale
+          fc.double({ min: 1, max: 100, noNaN: true }),    // max/min ratio
+          (width, height, scale, minScale, ratio) => {
+            const maxScale = minScale * ratio;
+            const effective = Math.min(maxScale, Math.max(minScale, scale));
+
+            setBounds();
+            const expected = new Uint32Array(width * height);
+            renderViewport(width, height, -0.5, 0.1, effective, 100, 2.0, expected);
+
+            expect(setBounds(minScale, maxScale)).toBe(true);
+            const buf = new Uint32Array(width * height);
+            const result = renderViewport(width, height, -0.5, 0.1, scale, 100, 2.0, buf);
+            if (effective === scale) {
+              expect(result).toBe(width * height);
+            } else {
+              expect(result).toEqual({ written: width * height, clamped: true, scale: effective });
+            }
+            expect(Array.from(buf)).toEqual(Array.from(expected));
+          }
+        ),
+        { numRuns: 100 }
+      );
+
+      expect(setBounds(0, 1)).toHaveProperty('error');
+      expect(setBounds(2, 1)).toHaveProperty('error');
+    } finally {
+      setBounds();
+    }
+  });
+
+
+  // Feature: mandelbrot-visualizer, Property 4at: Blend renders interpolate Mandelbrot and Julia counts
+  test('Property 4at: renderBlend combines the Mandelbrot and Julia counts of each pixel', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),                // width
+        fc.integer({ min: 1, max: 12 }),                // height
+        fc.double({ min: -1, max: 1, noNaN: true }),    // c real
+        fc.double({ min: -1, max: 1, noNaN: true }),    // c imag
+        fc.double({ min: 0, max: 1, noNaN: true }),     // blend
+        fc.integer({ min: 1, max: 200 }),               // max_iterations
+        (width, height, cReal, cImag, blend, maxIterations) => {
+          const scale = 3 / width;
+          const pixels = width * height;
+          const realCoords = new Float64Array(pixels);
+          const imagCoords = new Float64Array(pixels);
+          generateCoordinates(width, height, -0.5, 0, scale, realCoords, imagCoords);
+          const mandelbrot = new Uint32Array(pixels);
+          renderViewport(width, height, -0.5, 0, scale, maxIterations, 2.0, mandelbrot);
+          const julia = calculateJuliaSet(Array.from(realCoords), Array.from(imagCoords), cReal, cImag, maxIterations, 2.0);
+
+          const resultBuf = new Uint32Array(pixels);
+          expect(renderBlend(width, height, -0.5, 0, scale, cReal, cImag, blend, maxIterations, 2.0, resultBuf)).toBe(pixels);
+          for (let i = 0; i < pixels; i++) {
+            expect(resultBuf[i]).toBe(Math.round((1 - blend) * mandelbrot[i] + blend * julia[i]));
+          }
+
+          renderBlend(width, height, -0.5, 0, scale, cReal, cImag, 0, maxIterations, 2.0, resultBuf);
+          expect(Array.from(resultBuf)).toEqual(Array.from(mandelbrot));
+          renderBlend(width, height, -0.5, 0, scale, cReal, cImag, 1, maxIterations, 2.0, resultBuf);
+          expect(Array.from(resultBuf)).toEqual(julia);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(renderBlend(2, 2, 0, 0, 1, 0, 0, 1.5, 100, 2.0, new Uint32Array(4))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4au: hashBuffer is FNV-1a 64 of the buffer bytes
+  test('Property 4au: hashBuffer matches a reference FNV-1a 64 hash', () => {
+    const fnv1a64 = (bytes) => {
+      let hash = 0xcbf29ce484222325n;
+      for (const byte of bytes) {
+        hash = ((hash ^ BigInt(byte)) * 0x100000001b3n) & 0xffffffffffffffffn;
+      }
+      return hash.toString(16).padStart(16, '0');
+    };
+
+    fc.assert(
+      fc.property(
+        fc.array(fc.nat({ max: 0xffffffff }), { maxLength: 64 }),
+        fc.nat({ max: 8 }), // subarray start
+        (values, start) => {
+          const counts = new Uint32Array(values);
+          const view = counts.subarray(Math.min(start, counts.length));
+          const bytes = new Uint8Array(view.buffer, view.byteOffset, view.byteLength);
+
+          const hash = hashBuffer(view);
+          expect(hash).toMatch(/^[0-9a-f]{16}$/);
+          expect(hash).toBe(fnv1a64(bytes));
+          // Same bytes, different element type
+          expect(hashBuffer(bytes)).toBe(hash);
+          expect(hashBuffer(view.slice())).toBe(hash);
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // Known FNV-1a 64 vectors
+    expect(hashBuffer(new Uint8Array(0))).toBe('cbf29ce484222325');
+    expect(hashBuffer(new TextEncoder().encode('a'))).toBe('af63dc4c8601ec8c');
+
+    // A single changed count changes the hash
+    const counts = new Uint32Array([1, 2, 3, 4]);
+    const changed = counts.slice();
+    changed[2] = 5;
+    expect(hashBuffer(changed)).not.toBe(hashBuffer(counts));
+
+    expect(hashBuffer([1, 2, 3])).toHaveProperty('error');
+    expect(hashBuffer()).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 5m: Lookup table colors follow iteration counts
+  test('Property 5m: renderRGBAWithLUT colors each pixel by iterations modulo the table', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }),  // width
+        fc.integer({ min: 1, max: 16 }),  // height
+        fc.double({ min: -1.5, max: 0.5, noNaN: true }), // centerReal
+        fc.double({ min: -1, max: 1, noNaN: true }),     // centerImag
+        fc.double({ min: 0.01, max: 0.2, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 200 }), // maxIterations
+        fc.array(fc.integer({ min: 0, max: 255 }), { minLength: 3, maxLength: 30 }),
+        (width, height, centerReal, centerImag, scale, maxIterations, bytes) => {
+          const lut = new Uint8Array(bytes.slice(0, bytes.length - (bytes.length % 3)));
+          const entries = lut.length / 3;
+
+          const counts = new Uint32Array(width * height);
+          renderViewport(width, height, centerReal, centerImag, scale, maxIterations, 2.0, counts);
+          const pixels = new Uint8ClampedArray(width * height * 4);
+          expect(renderRGBAWithLUT(width, height, centerReal, centerImag, scale, maxIterations, 2.0, lut, pixels))
+            .toBe(width * height);
+
+          for (let i = 0; i < counts.length; i++) {
+            const expected = counts[i] === maxIterations
+              ? [0, 0, 0]
+              : Array.from(lut.subarray((counts[i] % entries) * 3, (counts[i] % entries) * 3 + 3));
+            expect(Array.from(pixels.subarray(i * 4, i * 4 + 4))).toEqual([...expected, 255]);
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    const pixels = new Uint8ClampedArray(16);
+    expect(renderRGBAWithLUT(2, 2, 0, 0, 1, 10, 2.0, new Uint8Array(0), pixels)).toHaveProperty('error');
+    expect(renderRGBAWithLUT(2, 2, 0, 0, 1, 10, 2.0, new Uint8Array(4), pixels)).toHaveProperty('error');
+    expect(renderRGBAWithLUT(2, 2, 0, 0, 1, 10, 2.0, new Uint8Array(3), new Uint8ClampedArray(15))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4av: Delta encoding round-trips iteration counts
+  test('Property 4av: decodeDeltas restores the counts encodeDeltas encoded', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 16 }), // width
+        fc.integer({ min: 0, max: 8 }),  // rows
+        fc.array(fc.nat({ max: 0xffffffff }), { minLength: 128, maxLength: 128 }),
+        (width, rows, values) => {
+          const counts = new Uint32Array(values.slice(0, width * rows));
+          const stream = encodeDeltas(counts, width);
+          expect(stream).toBeInstanceOf(Uint8Array);
+
+          const decoded = new Uint32Array(counts.length);
+          expect(decodeDeltas(stream, width, decoded)).toBe(counts.length);
+          expect(Array.from(decoded)).toEqual(Array.from(counts));
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    // A rendered tile shrinks to mostly single-byte deltas and round-trips
+    const width = 64;
+    const counts = new Uint32Array(width * width);
+    renderViewport(width, width, -0.5, 0, 3 / width, 500, 2.0, counts);
+    const stream = encodeDeltas(counts, width);
+    expect(stream.length).toBeLessThan(counts.byteLength / 2);
+    const decoded = new Uint32Array(counts.length);
+    expect(decodeDeltas(stream, width, decoded)).toBe(counts.length);
+    expect(Array.from(decoded)).toEqual(Array.from(counts));
+
+    // Each row starts from 0: [5, 5] per row encodes as zigzag 10, 0
+    expect(Array.from(encodeDeltas(new Uint32Array([5, 5, 5, 5]), 2))).toEqual([10, 0, 10, 0]);
+
+    expect(encodeDeltas(new Uint32Array(5), 2)).toHaveProperty('error');
+    expect(decodeDeltas(new Uint8Array([0x80]), 2, new Uint32Array(4))).toHaveProperty('error');
+    expect(decodeDeltas(new Uint8Array([0, 0, 0]), 2, new Uint32Array(2))).toHaveProperty('error');
+    // -1 from a row start would be a negative count
+    expect(decodeDeltas(new Uint8Array([1]), 2, new Uint32Array(2))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 3n: External ray points lie on the ray
+  test('Property 3n: traceExternalRay points solve the ray equation for their depth', () => {
+    const pointsOf = (flat) => {
+      const points = [];
+      for (let i = 0; i < flat.length; i += 2) points.push([flat[i], flat[i + 1]]);
+      return points;
+    };
+
+    fc.assert(
+      fc.property(
+        fc.double({ min: 0, max: 1, maxExcluded: true, noNaN: true }), // angle
+        fc.integer({ min: 1, max: 12 }), // depth
+        (angle, depth) => {
+          const points = pointsOf(traceExternalRay(angle, depth, 64));
+          expect(points.length).toBe(1 + 4 * depth);
+
+          const first = points[0];
+          expect(first[0]).toBeCloseTo(65536 * Math.cos(2 * Math.PI * angle), 6);
+          expect(first[1]).toBeCloseTo(65536 * Math.sin(2 * Math.PI * angle), 6);
+
+          // Point 4k + j + 1 solves z_(k+1)(c) = r * e^(2 pi i angle 2^k)
+          let turns = angle;
+          for (let k = 0; k < depth; k++) {
+            for (let j = 0; j < 4; j++) {
+              const [cr, ci] = points[4 * k + j + 1];
+              let zr = 0, zi = 0;
+              for (let n = 0; n <= k; n++) {
+                [zr, zi] = [zr * zr - zi * zi + cr, 2 * zr * zi + ci];
+              }
+              const radius = Math.pow(65536, Math.pow(0.5, (j + 0.5) / 4));
+              expect(Math.hypot(zr, zi) / radius).toBeCloseTo(1, 6);
+              const phase = Math.atan2(zi, zr) / (2 * Math.PI) - turns;
+              expect(Math.abs(phase - Math.round(phase))).toBeLessThan(1e-6);
+            }
+            turns = (turns * 2) % 1;
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // Rays 0 and 1/2 run along the real axis to 1/4 and -2
+    const zero = pointsOf(traceExternalRay(0, 30, 64));
+    expect(zero.every(([, imag]) => imag === 0)).toBe(true);
+    expect(zero[zero.length - 1][0]).toBeCloseTo(0.25, 1);
+    const half = pointsOf(traceExternalRay(0.5, 30, 64));
+    expect(half[half.length - 1][0]).toBeCloseTo(-2, 6);
+
+    // Ray 1/3 lands at the root of the period-2 bulb
+    const third = pointsOf(traceExternalRay(1 / 3, 48, 64));
+    const landing = third[third.length - 1];
+    expect(Math.hypot(landing[0] + 0.75, landing[1])).toBeLessThan(0.1);
+
+    // Conjugate angles give conjugate rays
+    const ray = pointsOf(traceExternalRay(0.375, 30, 64));
+    const conjugate = pointsOf(traceExternalRay(-0.375, 30, 64));
+    expect(conjugate.length).toBe(ray.length);
+    ray.forEach(([real, imag], i) => {
+      expect(conjugate[i][0]).toBeCloseTo(real, 6);
+      expect(conjugate[i][1]).toBeCloseTo(-imag, 6);
+    });
+
+    expect(traceExternalRay(NaN, 4, 64)).toHaveProperty('error');
+    expect(traceExternalRay(0, 0, 64)).toHaveProperty('error');
+    expect(traceExternalRay(0, 64, 1)).not.toHaveProperty('error');
+    expect(traceExternalRay(0.1, 65, 10)).toHaveProperty('error');
+    expect(traceExternalRay(0.1, 1e9, 10)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 6e: maxIterations beyond the limit is rejected
+  test('Property 6e: setMaxIterationsLimit bounds every maxIterations argument', () => {
+    try {
+      fc.assert(
+        fc.property(
+          fc.integer({ min: 1, max: 1000 }), // limit
+          fc.integer({ min: 1, max: 2000 }), // maxIterations
+          (limit, maxIterations) => {
+            expect(setMaxIterationsLimit(limit)).toBe(true);
+            const point = calculatePoint(-0.5, 0, maxIterations, 2.0);
+            const batch = calculateMandelbrotSet([-0.5], [0], maxIterations, 2.0);
+            if (maxIterations <= limit) {
+              expect(point).toBe(maxIterations);
+              expect(Array.from(batch)).toEqual([maxIterations]);
+            } else {
+              expect(point).toHaveProperty('error');
+              expect(point.error).toContain('at most');
+              expect(batch).toHaveProperty('error');
+            }
+            // Unbounded requests stop at the limit when it is below the cap
+            expect(calculatePoint(-0.5, 0, 0, 2.0)).toBe(limit);
+            expect(recommendedIterations(1e-300, limit, 32)).toBe(limit);
+          }
+        ),
+        { numRuns: 100 }
+      );
+
+      // Lowering the limit lowers the stored default
+      expect(setMaxIterationsLimit(10000000)).toBe(true);
+      expect(setDefaults(500, 2.0)).toBe(true);
+      expect(setMaxIterationsLimit(100)).toBe(true);
+      expect(calculatePoint(-0.5, 0)).toBe(100);
+    } finally {
+      setMaxIterationsLimit(10000000);
+      setDefaults(256, 2.0);
+    }
+
+    // Values that would wrap or hang are rejected by default
+    const resultBuf = new Uint32Array(4);
+    for (const bad of [-1, 0, NaN, 1e7 + 1, 2 ** 32, 2 ** 53, Infinity]) {
+      expect(renderViewport(2, 2, -0.5, 0, 0.1, bad, 2.0, resultBuf)).toHaveProperty('error');
+      // calculatePoint treats 0 and negative counts as unbounded
+      if (bad > 0 || Number.isNaN(bad)) {
+        expect(calculatePoint(-0.5, 0.1, bad, 2.0, false)).toHaveProperty('error');
+      }
+    }
+    expect(calculatePoint(-0.5, 0, 1e7, 2.0)).toBe(1e7);
+
+    // Iteration counts that aren't called maxIterations are bounded too
+    expect(calculateLemniscateLevel(0, 0, 1e10, 2)).toHaveProperty('error');
+    expect(calculateLemniscateLevel(0, 0, 0, 2)).toBe(0);
+    expect(findNearbyPeriodicPoint(0, 0, 1e10, 10)).toHaveProperty('error');
+    expect(calculateMultibrotPoint(0, 0, 1e9, 1000, 0)).toHaveProperty('error');
+    expect(calculateMultibrotPoint(0, 0, 65, 1000, 2.0)).toHaveProperty('error');
+    expect(calculateMultibrotPoint(0, 0, 64, 1000, 2.0)).toBe(1000);
+
+    expect(setMaxIterationsLimit(0)).toHaveProperty('error');
+    expect(setMaxIterationsLimit(2 ** 32)).toHaveProperty('error');
+    expect(setMaxIterationsLimit(1.5)).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4aw: Scanlines match the smooth render row by row
+  test('Property 4aw: computeScanline equals calculatePoint with smooth along the row', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 24 }),  // width
+        fc.integer({ min: 1, max: 24 }),  // height
+        fc.double({ min: -1.5, max: 0.5, noNaN: true }), // centerReal
+        fc.double({ min: -1, max: 1, noNaN: true }),     // centerImag
+        fc.double({ min: 0.001, max: 0.2, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 300 }), // maxIterations
+        fc.nat(),                          // row seed
+        (width, height, centerReal, centerImag, scale, maxIterations, seed) => {
+          const y = seed % height;
+          const row = computeScanline(y, width, height, centerReal, centerImag, scale, maxIterations, 2.0);
+          expect(row).toBeInstanceOf(Float64Array);
+          expect(row.length).toBe(width);
+
+          const image = new Float32Array(width * height);
+          renderSmoothFloat32(width, height, centerReal, centerImag, scale, maxIterations, 2.0, image);
+          for (let x = 0; x < width; x++) {
+            const real = centerReal + (x - width / 2) * scale;
+            const imag = centerImag - (y - height / 2) * scale;
+            expect(row[x]).toBeCloseTo(calculatePoint(real, imag, maxIterations, 2.0, true), 9);
+            expect(Math.fround(row[x])).toBe(image[y * width + x]);
+          }
+        }
+      ),
+      { numRuns: 100 }
+    );
+
+    expect(computeScanline(-1, 4, 4, 0, 0, 0.1, 100, 2.0)).toHaveProperty('error');
+    expect(computeScanline(4, 4, 4, 0, 0, 0.1, 100, 2.0)).toHaveProperty('error');
+    expect(computeScanline(0, 4, 0, 0.1, 100, 2.0)).toHaveProperty('error');
+    expect(computeScanline(0, 1e11, 1, -0.5, 0, 3, 10, 2)).toHaveProperty('error');
+    expect(computeScanline(0, 65537, 1, -0.5, 0, 3, 10, 2)).toHaveProperty('error');
+    expect(computeScanline(0, 65536, 1, -0.5, 0, 3 / 65536, 1, 2)).toHaveLength(65536);
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ax: Normals follow central differences of the smooth field
+  test('Property 4ax: renderNormals matches finite differences of calculatePoint smooth counts', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 1, max: 12 }),  // width
+        fc.integer({ min: 1, max: 12 }),  // height
+        fc.double({ min: -1.5, max: 0.5, noNaN: true }), // centerReal
+        fc.double({ min: -1, max: 1, noNaN: true }),     // centerImag
+        fc.double({ min: 0.005, max: 0.2, noNaN: true }), // scale
+        fc.integer({ min: 1, max: 200 }), // maxIterations
+        (width, height, centerReal, centerImag, scale, maxIterations) => {
+          // Smooth counts including a one pixel border; null marks interior points
+          const field = (x, y) => {
+            const real = centerReal + (x - width / 2) * scale;
+            const imag = centerImag - (y - height / 2) * scale;
+            if (calculatePoint(real, imag, maxIterations, 2.0) === maxIterations) return null;
+            return calculatePoint(real, imag, maxIterations, 2.0, true);
+          };
+          const slope = (before, center, after) => {
+            if (before !== null && after !== null) return (after - before) / 2;
+            if (after !== null) return after - center;
+            if (before !== null) return center - before;
+            return 0;
+          };
+
+          const normals = new Float32Array(width * height * 2);
+          expect(renderNormals(width, height, centerReal, centerImag, scale, maxIterations, 2.0, normals))
+            .toBe(width * height);
+
+          for (let y = 0; y < height; y++) {
+            for (let x = 0; x < width; x++) {
+              const center = field(x, y);
+              let nx = 0, ny = 0;
+              if (center !== null) {
+                const sx = slope(field(x - 1, y), center, field(x + 1, y));
+                const sy = slope(field(x, y - 1), center, field(x, y + 1));
+                const length = Math.hypot(sx, sy, 1);
+                nx = -sx / length;
+                ny = -sy / length;
+              }
+              const i = (y * width + x) * 2;
+              expect(normals[i]).toBeCloseTo(nx, 5);
+              expect(normals[i + 1]).toBeCloseTo(ny, 5);
+              expect(normals[i] ** 2 + normals[i + 1] ** 2).toBeLessThanOrEqual(1);
+            }
+          }
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    expect(renderNormals(2, 2, 0, 0, 0.1, 100, 2.0, new Float32Array(7))).toHaveProperty('error');
+    expect(renderNormals(2, 2, 0, 0, 0.1, 100, 2.0, new Float64Array(8))).toHaveProperty('error');
+  });
+
+  // Feature: mandelbrot-visualizer, Property 4ay: Cancellation stops only the render in progress
+  test('Property 4ay: cancelRender marks a partial render and is cleared by the next render', () => {
+    fc.assert(
+      fc.property(
+        fc.integer({ min: 8, max: 48 }),               // width
+        fc.integer({ min: 12, max: 48 }),              // height
+        fc.double({ min: -2, max: 0.5, noNaN: true }), // center real
+        fc.double({ min: -1, max: 1, noNaN: true }),   // center imag
+        fc.integer({ min: 1, max: 4 }),                // progress rows
+        (width, height, centerReal, centerImag, progressRows) => {
+          const pixels = width * height;
+          const expected = new Uint32Array(pixels);
+          expect(renderViewport(width, height, centerReal, centerImag, 0.05, 100, 2.0, expected)).toBe(pixels);
+
+          // Cancel from the first progress report; later rows are left unwritten
+          const resultBuf = new Uint32Array(pixels).fill(0xFFFFFFFF);
+          let reports = 0;
+          const result = renderViewport(width, height, centerReal, centerImag, 0.05, 100, 2.0, resultBuf, false, 4, 1, false,
+            () => {
+              reports++;
+              expect(cancelRender()).toBeNull();
+            }, progressRows);
+          expect(reports).toBeGreaterThanOrEqual(1);
+          expect(result.cancelled).toBe(true);
+          expect(result.written).toBeGreaterThan(0);
+          expect(result.written).toBeLessThan(pixels);
+          expect(Array.from(resultBuf.subarray(0, result.written))).toEqual(Array.from(expected.subarray(0, result.written)));
+
+          // The next render starts with the flag cleared and runs to the end
+          expect(renderViewport(width, height, centerReal, centerImag, 0.05, 100, 2.0, resultBuf)).toBe(pixels);
+          expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+        }
+      ),
+      { numRuns: 50 }
+    );
+
+    // A cancellation made before a render doesn't reach it
+    const realCoords = Array.from({ length: 3000 }, (_, i) => -2 + i / 1200);
+    const imagCoords = new Array(3000).fill(0.1);
+    const expected = calculateMandelbrotSet(realCoords, imagCoords, 100, 2.0);
+    cancelRender();
+    const batch = calculateMandelbrotSet(realCoords, imagCoords, 100, 2.0);
+    expect(batch.cancelled).toBeUndefined();
+    expect(batch).toEqual(expected);
+    cancelRender();
+    const resultBuf = new Uint32Array(3000);
+    expect(calculateMandelbrotSetTyped(new Float64Array(realCoords), new Float64Array(imagCoords), resultBuf, 100, 2.0)).toBe(3000);
+    expect(Array.from(resultBuf)).toEqual(Array.from(expected));
+    cancelRender();
+    expect(renderViewport(16, 16, -0.5, 0, 0.1, 100, 2.0, new Uint32Array(256))).toBe(256);
+  });
 });
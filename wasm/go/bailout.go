@@ -0,0 +1,41 @@
+package main
+
+import (
+	"math"
+)
+
+// Bailout shapes accepted by setBailoutShape
+const (
+	circleBailout = "circle"
+	squareBailout = "square"
+)
+
+// bailoutShape is the escape test escapeTime uses, changed from JavaScript
+// via setBailoutShape or for a single call by a bailoutShape argument
+var bailoutShape = circleBailout
+
+// escapeTimeSquare is escapeTime with a square bailout: z escapes once either
+// component exceeds the escape radius in magnitude
+//
+// The square contains the circle, so points escape no earlier than with the
+// circular test and the escape bands take on square corners. Returns the
+// iteration count and |z|^2 at that point, as escapeTime does.
+func escapeTimeSquare(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	escapeRadius := math.Sqrt(escapeRadiusSquared)
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Check if either component has escaped
+		if math.Abs(zReal) > escapeRadius || math.Abs(zImag) > escapeRadius {
+			return iteration, zReal*zReal + zImag*zImag
+		}
+
+		// Calculate z = z^2 + c
+		// (a + bi)^2 = a^2 - b^2 + 2abi
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
@@ -0,0 +1,48 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// setBailoutShape selects the escape test of calculatePoint, the batch
+// functions and every other function iterating with escapeTime, the default
+// for calls without a bailoutShape argument
+//
+// Parameters:
+//   - shape: "circle" (|z|^2 > escapeRadius^2, the default) or "square"
+//     (|zReal| > escapeRadius or |zImag| > escapeRadius)
+//
+// Returns:
+//   - true when the shape was selected, {error} for unknown shapes
+func setBailoutShape(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setBailoutShape", args, 1)
+	shape := r.str(0, "shape")
+	r.check(shape == circleBailout || shape == squareBailout, "unknown bailout shape %q", shape)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	bailoutShape = shape
+	return true
+}
+
+// bailoutShape returns the optional bailout shape argument at index, or the
+// shape selected with setBailoutShape when it is omitted, null or undefined
+func (r *argReader) bailoutShape(index int) string {
+	if !r.has(index) || r.value(index).IsNull() || r.value(index).IsUndefined() {
+		return bailoutShape
+	}
+	shape := r.str(index, "bailoutShape")
+	r.check(shape == circleBailout || shape == squareBailout, "unknown bailout shape %q", shape)
+	return shape
+}
+
+// useBailoutShape selects shape for the rest of a call, returning a function
+// that restores the shape selected before
+func useBailoutShape(shape string) (restore func()) {
+	saved := bailoutShape
+	bailoutShape = shape
+	return func() { bailoutShape = saved }
+}
@@ -0,0 +1,18 @@
+//go:build js && !goexperiment.simd
+
+package main
+
+// simdEnabled reports whether this build vectorizes the batch loop
+const simdEnabled = false
+
+// mandelbrotEscapeTimes stores the Iterate count of every point
+// (realCoords[i], imagCoords[i]) in results[i], reporting proven interior
+// points as the interior value when one is set
+//
+// This is the scalar implementation; building with GOEXPERIMENT=simd selects
+// the vectorized one in batch_simd.go, which returns identical results.
+func mandelbrotEscapeTimes(results []uint32, realCoords, imagCoords []float64, maxIterations uint32, escapeRadiusSquared float64) {
+	for i := range results {
+		results[i] = iterateReported(realCoords[i], imagCoords[i], maxIterations, escapeRadiusSquared)
+	}
+}
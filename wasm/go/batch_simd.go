@@ -0,0 +1,167 @@
+//go:build js && goexperiment.simd
+
+package main
+
+import (
+	"math"
+	"simd"
+)
+
+// SIMD batch iteration
+//
+// Built with GOEXPERIMENT=simd, the batch Mandelbrot loop iterates one SIMD
+// vector of points at a time, two float64 lanes with wasm's 128-bit SIMD.
+// Each lane performs exactly the operations of escapeTime in the same order,
+// and wasm has no fused multiply-add, so the counts are bit-for-bit those of
+// the scalar loop.
+//
+// Points in a batch escape after very different counts, so rather than
+// iterating a vector until its slowest lane finishes, a lane that finishes is
+// refilled with the next point while the other lanes carry on.
+
+// simdEnabled reports whether this build vectorizes the batch loop
+const simdEnabled = true
+
+// vectorCheckInterval is how many iterations run between checks for lanes
+// that have finished
+const vectorCheckInterval = 8
+
+// mandelbrotEscapeTimes stores the Iterate count of every point
+// (realCoords[i], imagCoords[i]) in results[i], reporting proven interior
+// points as the interior value when one is set
+//
+// Points resolved by the cardioid and bulb test, and NaN or infinite points,
+// are filled in directly and the rest are fed through the lanes of the
+// vectorized loop. Periodicity
+// checking and the square bailout have no vector form, so they fall back to
+// the scalar loop.
+func mandelbrotEscapeTimes(results []uint32, realCoords, imagCoords []float64, maxIterations uint32, escapeRadiusSquared float64) {
+	if periodicityCheck || bailoutShape == squareBailout {
+		for i := range results {
+			results[i] = iterateReported(realCoords[i], imagCoords[i], maxIterations, escapeRadiusSquared)
+		}
+		return
+	}
+
+	// Per-lane state, kept in memory only while lanes are refilled. index is
+	// the point a lane iterates, or -1 once the lane is idle; running is -1
+	// until the lane's |z|^2 exceeds the escape radius.
+	lanes := simd.BroadcastFloat64s(0).Len()
+	index := make([]int, lanes)
+	laneZReal := make([]float64, lanes)
+	laneZImag := make([]float64, lanes)
+	laneCReal := make([]float64, lanes)
+	laneCImag := make([]float64, lanes)
+	laneCount := make([]int64, lanes)
+	laneRunning := make([]int64, lanes)
+
+	// refill starts the next point that needs iterating in every lane that
+	// has finished, storing the finished lane's count, and returns whether
+	// any lane is still busy
+	next := 0
+	refill := func() bool {
+		busy := false
+		for lane := 0; lane < lanes; lane++ {
+			if index[lane] >= 0 && laneRunning[lane] != 0 && laneCount[lane] < int64(maxIterations) {
+				busy = true
+				continue
+			}
+			if index[lane] >= 0 {
+				results[index[lane]] = uint32(laneCount[lane])
+			}
+
+			// Idle lanes hold NaN and never run
+			index[lane] = -1
+			laneZReal[lane], laneZImag[lane] = 0, 0
+			laneCReal[lane], laneCImag[lane] = math.NaN(), math.NaN()
+			laneCount[lane], laneRunning[lane] = 0, 0
+
+			for next < len(results) {
+				i := next
+				next++
+				if !isFinite(realCoords[i], imagCoords[i]) {
+					results[i] = 0
+					continue
+				}
+				if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(realCoords[i], imagCoords[i]) {
+					results[i] = reportedCount(maxIterations, true)
+					continue
+				}
+
+				index[lane] = i
+				laneCReal[lane], laneCImag[lane] = realCoords[i], imagCoords[i]
+				laneRunning[lane] = -1
+				busy = true
+				break
+			}
+		}
+		return busy
+	}
+
+	for lane := range index {
+		index[lane] = -1
+	}
+
+	two := simd.BroadcastFloat64s(2.0)
+	radius := simd.BroadcastFloat64s(escapeRadiusSquared)
+
+	for refill() {
+		zReal := simd.LoadFloat64s(laneZReal)
+		zImag := simd.LoadFloat64s(laneZImag)
+		cReal := simd.LoadFloat64s(laneCReal)
+		cImag := simd.LoadFloat64s(laneCImag)
+		count := simd.LoadInt64s(laneCount)
+		running := simd.LoadInt64s(laneRunning).ToMask()
+
+		// Iterations until the first running lane reaches maxIterations
+		budget := int64(maxIterations)
+		for lane := range index {
+			if laneRunning[lane] != 0 {
+				budget = min(budget, int64(maxIterations)-laneCount[lane])
+			}
+		}
+
+		for budget > 0 {
+			steps := min(budget, vectorCheckInterval)
+			budget -= steps
+
+			for step := int64(0); step < steps; step++ {
+				zRealSquared := zReal.Mul(zReal)
+				zImagSquared := zImag.Mul(zImag)
+
+				// Once a lane stops it stays stopped, even if z wanders back
+				// inside the escape radius
+				running = running.And(zRealSquared.Add(zImagSquared).LessEqual(radius))
+
+				// Running lanes are all ones, -1 as integers
+				count = count.Sub(running.ToInt64s())
+
+				// z = z^2 + c
+				zRealTemp := zRealSquared.Sub(zImagSquared).Add(cReal)
+				zImag = two.Mul(zReal).Mul(zImag).Add(cImag)
+				zReal = zRealTemp
+			}
+
+			// Keep iterating until some busy lane has stopped
+			running.ToInt64s().Store(laneRunning)
+			if laneStopped(index, laneRunning) {
+				break
+			}
+		}
+
+		zReal.Store(laneZReal)
+		zImag.Store(laneZImag)
+		count.Store(laneCount)
+	}
+}
+
+// laneStopped reports whether a lane that is iterating a point has stopped
+// running
+func laneStopped(index []int, running []int64) bool {
+	for lane, i := range index {
+		if i >= 0 && running[lane] == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+//go:build js
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestMandelbrotEscapeTimesMatchesScalar checks the batch loop, vectorized
+// in SIMD builds, against Iterate point by point
+func TestMandelbrotEscapeTimesMatchesScalar(t *testing.T) {
+	realCoords, imagCoords := gridPoints(97, 61)
+
+	tests := []struct {
+		name          string
+		maxIterations uint32
+		escapeRadius  float64
+		periodicity   bool
+		bailout       string
+	}{
+		{"single iteration", 1, 2, false, circleBailout},
+		{"default radius", 256, 2, false, circleBailout},
+		{"large radius", 100, 1e3, false, circleBailout},
+		{"radius below 2 skips the cardioid test", 100, 0.5, false, circleBailout},
+		{"periodicity checking", 500, 2, true, circleBailout},
+		{"square bailout", 256, 2, false, squareBailout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(saved bool) { periodicityCheck = saved }(periodicityCheck)
+			periodicityCheck = tt.periodicity
+			defer func(saved string) { bailoutShape = saved }(bailoutShape)
+			bailoutShape = tt.bailout
+
+			escapeRadiusSquared := tt.escapeRadius * tt.escapeRadius
+			results := make([]uint32, len(realCoords))
+			mandelbrotEscapeTimes(results, realCoords, imagCoords, tt.maxIterations, escapeRadiusSquared)
+
+			for i, got := range results {
+				want := Iterate(realCoords[i], imagCoords[i], tt.maxIterations, escapeRadiusSquared)
+				if got != want {
+					t.Fatalf("point (%v, %v): got %d iterations, want %d", realCoords[i], imagCoords[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestMandelbrotEscapeTimesPartialVectors checks lengths that leave a
+// partly filled final vector
+func TestMandelbrotEscapeTimesPartialVectors(t *testing.T) {
+	realCoords := []float64{0.3, -0.75, 0.25, 1, -2, 0.4, -0.1}
+	imagCoords := []float64{0.5, 0.1, 0, 1, 0, 0.3, 0.9}
+
+	for length := 0; length <= len(realCoords); length++ {
+		results := make([]uint32, length)
+		mandelbrotEscapeTimes(results, realCoords[:length], imagCoords[:length], 100, 4)
+
+		for i, got := range results {
+			if want := Iterate(realCoords[i], imagCoords[i], 100, 4); got != want {
+				t.Errorf("length %d, point %d: got %d iterations, want %d", length, i, got, want)
+			}
+		}
+	}
+}
+
+// TestMandelbrotEscapeTimesNonFinite checks that NaN and infinite points
+// escape at iteration 0 in the batch loop, among finite points on either
+// side of them in the same vectors
+func TestMandelbrotEscapeTimesNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	realCoords := []float64{nan, 0, -inf, 0.3, inf, 1, 0, -0.75, nan}
+	imagCoords := []float64{0, nan, 0, 0.5, inf, 1, -inf, 0.1, nan}
+	want := []uint32{0, 0, 0, Iterate(0.3, 0.5, 100, 4), 0, Iterate(1, 1, 100, 4), 0, Iterate(-0.75, 0.1, 100, 4), 0}
+
+	results := make([]uint32, len(realCoords))
+	mandelbrotEscapeTimes(results, realCoords, imagCoords, 100, 4)
+	for i, got := range results {
+		if got != want[i] {
+			t.Errorf("point (%v, %v): got %d iterations, want %d", realCoords[i], imagCoords[i], got, want[i])
+		}
+	}
+}
+
+// BenchmarkEscapeTimeCheckInterval measures the scalar loop over the
+// benchmark points at several check intervals
+func BenchmarkEscapeTimeCheckInterval(b *testing.B) {
+	realCoords, imagCoords := benchmarkPoints(4096)
+	defer func(saved int) { checkInterval = saved }(checkInterval)
+
+	for _, interval := range []int{1, 4, 8, 16} {
+		b.Run(fmt.Sprintf("interval %d", interval), func(b *testing.B) {
+			checkInterval = interval
+			for n := 0; n < b.N; n++ {
+				for i := range realCoords {
+					escapeTime(0, 0, realCoords[i], imagCoords[i], 2000, 4)
+				}
+			}
+		})
+	}
+}
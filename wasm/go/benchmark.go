@@ -0,0 +1,115 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+	"time"
+)
+
+// benchmarkSeed seeds the benchmark's point generator so every run iterates
+// the same points
+const benchmarkSeed = 0x9E3779B97F4A7C15
+
+// benchmarkBlockSize is the number of points benchmarkIterations generates
+// and iterates at a time, so memory use doesn't grow with the count
+const benchmarkBlockSize = 1 << 16
+
+// benchmarkPointSource generates the benchmark's fixed sequence of
+// pseudo-random points spread over the region real [-2, 0.5], imag
+// [-1.25, 1.25], which covers the whole set
+type benchmarkPointSource struct {
+	state uint64
+}
+
+// newBenchmarkPointSource returns a source at the start of the sequence
+func newBenchmarkPointSource() *benchmarkPointSource {
+	return &benchmarkPointSource{state: benchmarkSeed}
+}
+
+// next returns the next uniform value in [0, 1) from xorshift64*
+func (s *benchmarkPointSource) next() float64 {
+	s.state ^= s.state >> 12
+	s.state ^= s.state << 25
+	s.state ^= s.state >> 27
+	return float64((s.state*0x2545F4914F6CDD1D)>>11) / (1 << 53)
+}
+
+// fill stores the next len(realCoords) points of the sequence
+func (s *benchmarkPointSource) fill(realCoords, imagCoords []float64) {
+	for i := range realCoords {
+		realCoords[i] = -2.0 + 2.5*s.next()
+		imagCoords[i] = -1.25 + 2.5*s.next()
+	}
+}
+
+// benchmarkPoints returns the first count points of the benchmark sequence
+func benchmarkPoints(count int) ([]float64, []float64) {
+	realCoords := make([]float64, count)
+	imagCoords := make([]float64, count)
+	newBenchmarkPointSource().fill(realCoords, imagCoords)
+	return realCoords, imagCoords
+}
+
+// benchmarkIterations measures raw iteration throughput entirely inside Go
+//
+// The same fixed set of points is iterated on every call with the current
+// settings (periodicity checking included) by the same loop as the batch
+// functions, vectorized in SIMD builds, so results can be compared across
+// builds and settings. Points are generated and iterated in blocks of
+// benchmarkBlockSize, so any count runs in constant memory; generation is
+// not timed.
+//
+// Parameters:
+//   - count: Number of points to iterate
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - An object {iterations, nanoseconds} with the sum of the iteration counts
+//     of all points and the elapsed time. Points resolved without iterating,
+//     such as those inside the main cardioid, count as maxIterations so the
+//     total is the same whichever optimizations are enabled, and whatever
+//     value setInteriorValue reports for them. Returns {error} for invalid
+//     arguments.
+func benchmarkIterations(this js.Value, args []js.Value) interface{} {
+	r := readArgs("benchmarkIterations", args, 3)
+	count := r.integer(0, "count")
+	maxIterations := r.maxIterations(1)
+	escapeRadius := r.escapeRadius(2)
+	r.check(count >= 0, "count must not be negative, got %d", count)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// The batch loop reports proven interior points as the interior value;
+	// the benchmark counts them as maxIterations
+	defer func(saved bool) { interiorValueSet = saved }(interiorValueSet)
+	interiorValueSet = false
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+	source := newBenchmarkPointSource()
+	realCoords := make([]float64, min(count, benchmarkBlockSize))
+	imagCoords := make([]float64, len(realCoords))
+	results := make([]uint32, len(realCoords))
+
+	total := uint64(0)
+	var elapsed time.Duration
+	for done := 0; done < count; done += len(results) {
+		block := min(count-done, len(results))
+		source.fill(realCoords[:block], imagCoords[:block])
+
+		start := time.Now()
+		mandelbrotEscapeTimes(results[:block], realCoords[:block], imagCoords[:block], maxIterations, escapeRadiusSquared)
+		elapsed += time.Since(start)
+
+		for _, iterations := range results[:block] {
+			total += uint64(iterations)
+		}
+	}
+
+	return map[string]interface{}{
+		"iterations":  float64(total),
+		"nanoseconds": float64(elapsed.Nanoseconds()),
+	}
+}
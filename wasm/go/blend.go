@@ -0,0 +1,96 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Mandelbrot to Julia morphing
+//
+// Every pixel p has a Mandelbrot count, iterating z = z^2 + p from 0, and a
+// Julia count for a fixed c, iterating z = z^2 + c from p. Interpolating the
+// two by a blend factor and animating it from 0 to 1 gives the classic
+// transition in which the parameter slides from pixel-dependent to fixed.
+
+// fillBlend computes the blended count (1-blend)*M + blend*J of every pixel
+// in row-major order, rounded to the nearest integer, where M is its
+// Mandelbrot count and J its Julia count for (cReal, cImag)
+//
+// The Mandelbrot counts follow the precision settings like renderViewport;
+// since counts are averaged, the interior value of setInteriorValue is not
+// applied.
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillBlend(results []uint32, cReal, cImag, blend float64, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				real, imag := v.pointAt(x, y)
+				// Skip whichever count has no weight
+				var mandelbrot, julia uint32
+				if blend < 1 {
+					mandelbrot, _ = v.escapeTimeAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+				}
+				if blend > 0 {
+					julia = IterateJulia(real, imag, cReal, cImag, maxIterations, escapeRadiusSquared)
+				}
+				results[y*v.width+x] = uint32(math.Round((1-blend)*float64(mandelbrot) + blend*float64(julia)))
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
+
+// renderBlend renders a viewport blending each pixel's Mandelbrot iteration
+// count with its Julia iteration count for a fixed c
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - cReal, cImag: The fixed Julia parameter c
+//   - blend: Weight of the Julia count, in [0, 1]: 0 gives the Mandelbrot
+//     counts, 1 the Julia counts
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least width*height elements receiving
+//     round((1-blend)*M + blend*J) per pixel in row-major order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderBlend(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderBlend", args, 11)
+	view := r.viewport(0)
+	cReal := r.number(5, "cReal")
+	cImag := r.number(6, "cImag")
+	blend := r.number(7, "blend")
+	r.check(blend >= 0 && blend <= 1, "blend must be between 0 and 1, got %v", blend)
+	maxIterations := r.maxIterations(8)
+	escapeRadius := r.escapeRadius(9)
+	resultBuf := r.typedArray(10, "resultBuf", "Uint32Array")
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results := make([]uint32, view.pixelCount())
+	completed := view.fillBlend(results, cReal, cImag, blend, maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,77 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Scale bounds
+//
+// A frontend zoom bug can pass renderViewport a scale so small that float64
+// can no longer tell neighboring pixels apart, or so large that the whole set
+// shrinks to a dot, and the output is meaningless either way. After
+// setBounds(minScale, maxScale), renderViewport clamps the scale into that
+// range and reports that it did, so the frontend learns it left the module's
+// usable range. No bounds are set by default.
+
+// Scale limits applied by renderViewport, changed from JavaScript via
+// setBounds
+var (
+	minViewScale = 0.0
+	maxViewScale = math.Inf(1)
+)
+
+// setBounds sets the range renderViewport clamps its scale to, or removes it
+//
+// Parameters:
+//   - minScale: Smallest allowed scale, greater than 0
+//   - maxScale: Largest allowed scale, at least minScale
+//
+// Called with no arguments, the bounds are removed.
+//
+// Returns:
+//   - true when the bounds were applied, {error} for invalid arguments
+func setBounds(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setBounds", args, 0, 2)
+	if r.failed() {
+		return r.errorResult()
+	}
+	if !r.has(0) {
+		minViewScale, maxViewScale = 0, math.Inf(1)
+		return true
+	}
+	minScale := r.number(0, "minScale")
+	maxScale := r.number(1, "maxScale")
+	r.check(minScale > 0, "minScale must be greater than 0, got %v", minScale)
+	r.check(maxScale >= minScale, "maxScale must be at least minScale %v, got %v", minScale, maxScale)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	minViewScale, maxViewScale = minScale, maxScale
+	return true
+}
+
+// clampScale returns the viewport with the magnitude of scaleX clamped to the
+// bounds set by setBounds, scaling scaleY by the same factor to keep the pixel
+// aspect ratio, and whether clamping changed it
+//
+// The sign is kept, so a mirrored view stays mirrored. A scale of 0 is
+// clamped to minScale.
+func (v viewport) clampScale() (viewport, bool) {
+	magnitude := math.Abs(v.scaleX)
+	clamped := math.Max(minViewScale, math.Min(maxViewScale, magnitude))
+	if clamped == magnitude || math.IsNaN(magnitude) {
+		return v, false
+	}
+
+	if magnitude == 0 {
+		v.scaleX, v.scaleY = clamped, clamped
+		return v, true
+	}
+	v.scaleY *= clamped / magnitude
+	v.scaleX = math.Copysign(clamped, v.scaleX)
+	return v, true
+}
@@ -0,0 +1,84 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Buddhabrot accumulation
+//
+// The Buddhabrot plots where orbits go rather than where they start: every
+// escaping sample point adds one to each pixel its orbit passes through, and
+// the accumulated density is the image. Only escaping orbits contribute, so
+// each sample is first iterated to its escape time and then replayed.
+
+// accumulateOrbit replays the orbit z_1 = c, z_2, ... of an escaping point up
+// to and including the escaping value, and appends the index of each pixel of
+// view the orbit visits to hits, once per visit
+//
+// Returns hits unchanged if the point does not escape within maxIterations.
+func (v viewport) accumulateOrbit(hits []int, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) []int {
+	if Iterate(cReal, cImag, maxIterations, escapeRadiusSquared) >= maxIterations {
+		return hits
+	}
+
+	walkOrbit(cReal, cImag, maxIterations, escapeRadiusSquared, func(zReal, zImag float64) bool {
+		if x, y, ok := v.pixelAt(zReal, zImag); ok {
+			hits = append(hits, y*v.width+x)
+		}
+		return true
+	})
+	return hits
+}
+
+// accumulateBuddhabrot adds the orbit of one sample point to a Buddhabrot
+// density buffer
+//
+// Parameters:
+//   - sampleReal: Real component of the sample point c
+//   - sampleImag: Imaginary component of the sample point c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - width: View width in pixels
+//   - height: View height in pixels
+//   - viewCenterReal: Real component at the center of the view
+//   - viewCenterImag: Imaginary component at the center of the view
+//   - scale: Complex-plane units per pixel
+//   - densityBuf: Uint32Array of at least width*height elements in row-major
+//     order; each pixel an orbit value falls on (nearest sample point, as
+//     renderViewport maps pixels) is incremented once per visit
+//
+// Returns:
+//   - The number of increments made, which is 0 for points that don't escape
+//     within maxIterations, or {error} if the arguments or buffer are invalid
+func accumulateBuddhabrot(this js.Value, args []js.Value) interface{} {
+	r := readArgs("accumulateBuddhabrot", args, 10)
+	sampleReal := r.number(0, "sampleReal")
+	sampleImag := r.number(1, "sampleImag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	width, height := r.dimensions(4, 5, "width", "height")
+	view := viewport{
+		width:      width,
+		height:     height,
+		centerReal: r.number(6, "viewCenterReal"),
+		centerImag: r.number(7, "viewCenterImag"),
+		scaleX:     r.number(8, "scale"),
+	}
+	view.scaleY = view.scaleX
+	densityBuf := r.typedArray(9, "densityBuf", "Uint32Array")
+	r.minLength(densityBuf, "densityBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	hits := view.accumulateOrbit(nil, sampleReal, sampleImag, maxIterations, escapeThreshold(escapeRadius))
+
+	// An orbit touches few pixels compared with the whole buffer, so update
+	// them in place rather than copying the buffer both ways
+	for _, index := range hits {
+		densityBuf.SetIndex(index, uint32(densityBuf.Index(index).Float())+1)
+	}
+	return len(hits)
+}
@@ -0,0 +1,82 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// fillBudgeted computes pixels in row-major order from startIndex until every
+// pixel is done or the iteration counts computed so far reach
+// maxTotalIterations. At least one pixel is computed whenever any remain, so
+// every call makes progress.
+//
+// Returns the index of the first pixel that was not computed, which is
+// pixelCount once the viewport is complete, and whether the render was
+// cancelled.
+func (v viewport) fillBudgeted(results []uint32, startIndex int, maxTotalIterations uint64, maxIterations uint32, escapeRadiusSquared float64) (int, bool) {
+	total := uint64(0)
+	for index := startIndex; index < v.pixelCount(); index++ {
+		if total >= maxTotalIterations && index > startIndex {
+			return index, false
+		}
+		if (index-startIndex)%pointsPerCancelCheck == 0 && isRenderCancelled() {
+			return index, true
+		}
+
+		results[index], _ = v.escapeTimeAt(index%v.width, index/v.width, 0, 0, maxIterations, escapeRadiusSquared)
+		total += uint64(results[index])
+	}
+	return v.pixelCount(), false
+}
+
+// renderViewportBudget renders part of a viewport like renderViewport,
+// stopping once an iteration budget is spent so a long render can be spread
+// over several animation frames
+//
+// Pixels are computed one after another in row-major order starting at
+// startIndex. After each pixel its iteration count is added to a running
+// total, and the call returns as soon as the total reaches
+// maxTotalIterations. Interior pixels count maxIterations, even those the
+// cardioid test resolves without iterating, so the budget is never
+// underestimated. Pixels before startIndex are left as they are in resultBuf.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//   - maxTotalIterations: Iteration budget for this call, at least 1
+//   - startIndex: Index of the first pixel to compute, in [0, width*height]
+//
+// Returns:
+//   - The resume index, the index of the first pixel not yet computed, to pass
+//     as startIndex on the next call. It equals width*height once the whole
+//     viewport is rendered. {error} if the arguments or buffer are invalid.
+//     If cancelRender stops the render, {written, cancelled: true} where
+//     written counts the pixels computed from startIndex.
+func renderViewportBudget(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderViewportBudget", args, 10)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	resultBuf := r.typedArray(7, "resultBuf", "Uint32Array")
+	maxTotalIterations := r.positiveInteger(8, "maxTotalIterations")
+	startIndex := r.integer(9, "startIndex")
+	r.check(startIndex >= 0 && startIndex <= view.pixelCount(), "startIndex must be in [0, %d], got %d", view.pixelCount(), startIndex)
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results := make([]uint32, view.pixelCount())
+	next, cancelled := view.fillBudgeted(results, startIndex, uint64(maxTotalIterations), maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf.Call("subarray", startIndex, next), results[startIndex:next])
+	if cancelled {
+		return cancelledResult(next - startIndex)
+	}
+	return next
+}
@@ -0,0 +1,58 @@
+//go:build js
+
+package main
+
+import (
+	"sync/atomic"
+	"syscall/js"
+)
+
+// Render cancellation
+//
+// Batch and viewport loops poll renderCancelled between chunks of work and
+// stop early when it is set. Every render entry point clears the flag when it
+// starts, so a cancellation only affects the render in progress.
+//
+// Go functions called from JS run synchronously on the JS thread, so while a
+// render is running no other JS code runs unless the render itself calls back
+// into JS. cancelRender therefore takes effect when it is called from inside
+// such a callback, or from a goroutine while the render is waiting on one.
+
+// rowsPerCancelCheck is how many viewport rows are computed between checks
+const rowsPerCancelCheck = 4
+
+// pointsPerCancelCheck is how many batch points are computed between checks
+const pointsPerCancelCheck = 1024
+
+// renderCancelled is set by cancelRender and cleared by beginRender
+var renderCancelled atomic.Bool
+
+// cancelRender asks the render in progress to stop at its next check
+//
+// Returns:
+//   - null
+func cancelRender(this js.Value, args []js.Value) interface{} {
+	renderCancelled.Store(true)
+	return nil
+}
+
+// beginRender clears any cancellation left over from a previous render
+func beginRender() {
+	renderCancelled.Store(false)
+}
+
+// isRenderCancelled reports whether cancelRender was called since the
+// current render began
+func isRenderCancelled() bool {
+	return renderCancelled.Load()
+}
+
+// cancelledResult is the value returned by renders that write into a caller
+// buffer when they are cancelled: the number of leading elements that were
+// completed, and a cancelled marker
+func cancelledResult(written int) interface{} {
+	return map[string]interface{}{
+		"written":   written,
+		"cancelled": true,
+	}
+}
@@ -0,0 +1,43 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// moduleVersion is the version of the Go module's JavaScript API, following
+// the project version in package.json
+const moduleVersion = "1.0.0"
+
+// getCapabilities describes what this build of the module provides, for
+// feature detection and bug reports
+//
+// Returns:
+//   - An object with:
+//     version: moduleVersion
+//     simd: whether the batch loop is vectorized (GOEXPERIMENT=simd builds)
+//     highPrecision, fixedPoint, seriesApproximation: whether
+//     setHighPrecision, setFixedPoint and setSeriesApproximation are
+//     available, which they are in every build
+//     coloringModes, bailoutShapes, supersamplePatterns: the values accepted
+//     by setColoringMode, setBailoutShape and renderRGBA's pattern argument
+//     functions: the names of every exported function, in registration order
+func getCapabilities(this js.Value, args []js.Value) interface{} {
+	functions := make([]interface{}, len(registeredFuncs))
+	for i, registered := range registeredFuncs {
+		functions[i] = registered.name
+	}
+
+	return map[string]interface{}{
+		"version":             moduleVersion,
+		"simd":                simdEnabled,
+		"highPrecision":       true,
+		"fixedPoint":          true,
+		"seriesApproximation": true,
+		"coloringModes":       []interface{}{linearColoring, histogramColoring},
+		"bailoutShapes":       []interface{}{circleBailout, squareBailout},
+		"supersamplePatterns": []interface{}{gridPattern, rotatedGridPattern},
+		"functions":           functions,
+	}
+}
@@ -0,0 +1,333 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Direct RGBA rendering
+//
+// Escaped points are colored by the smooth iteration count relative to
+// maxIterations using the palette chosen with setPalette. The default rainbow
+// palette mirrors the frontend's colorPalette.js: a hue sweep around the HSV
+// color wheel at saturation 0.8 and value 0.9. Interior points are black.
+
+// rgb is an 8-bit-per-channel color
+type rgb struct {
+	r, g, b uint8
+}
+
+// interiorColor is used for points that never escape
+var interiorColor = rgb{0, 0, 0}
+
+// palette maps a normalized iteration fraction in [0, 1) to a color
+type palette func(t float64) rgb
+
+// palettes holds the built-in palettes by the name setPalette accepts
+var palettes = map[string]palette{
+	"grayscale": grayscalePalette,
+	"fire":      firePalette,
+	"ocean":     oceanPalette,
+	"rainbow":   rainbowPalette,
+}
+
+// currentPalette is the palette the RGBA renderer uses
+var currentPalette palette = rainbowPalette
+
+// maxPaletteTableSize bounds the lookup table setPalette may precompute
+const maxPaletteTableSize = 65536
+
+// setPalette selects the palette for renderRGBA
+//
+// Parameters:
+//   - name: One of "grayscale", "fire", "ocean" or "rainbow"
+//   - tableSize (optional): Precompute the palette into a lookup table of
+//     this many entries, from 2 to 65536, instead of evaluating it per pixel.
+//     0 (the default) evaluates the palette directly.
+//   - interpolate (optional): When true, blend linearly between the two
+//     nearest table entries instead of taking the nearest one
+//
+// Returns:
+//   - true when the palette was selected, {error} for unknown names or
+//     invalid table sizes
+func setPalette(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setPalette", args, 1, 2, 3)
+	name := r.str(0, "name")
+	selected, ok := palettes[name]
+	r.check(ok, "unknown palette %q", name)
+	tableSize := 0
+	if r.has(1) {
+		tableSize = r.integer(1, "tableSize")
+	}
+	r.check(tableSize == 0 || (tableSize >= 2 && tableSize <= maxPaletteTableSize), "tableSize must be 0 or from 2 to %d, got %d", maxPaletteTableSize, tableSize)
+	interpolate := r.flag(2)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	if tableSize > 0 {
+		selected = paletteTable(selected, tableSize, interpolate)
+	}
+	currentPalette = selected
+	return true
+}
+
+// paletteTable samples p at size evenly spaced positions from 0 to 1 and
+// returns a palette that looks colors up in the samples instead of
+// evaluating p, taking the nearest entry or, with interpolate, blending the
+// two nearest
+func paletteTable(p palette, size int, interpolate bool) palette {
+	table := make([]rgb, size)
+	last := float64(size - 1)
+	for i := range table {
+		table[i] = p(float64(i) / last)
+	}
+
+	return func(t float64) rgb {
+		// Written so that NaN, like t <= 0, takes the first entry
+		position := 0.0
+		if t > 0 {
+			position = math.Min(1, t) * last
+		}
+		if !interpolate {
+			return table[int(math.Round(position))]
+		}
+
+		index := min(int(position), size-2)
+		fraction := position - float64(index)
+		lower, upper := table[index], table[index+1]
+		return rgb{
+			r: blendChannel(lower.r, upper.r, fraction),
+			g: blendChannel(lower.g, upper.g, fraction),
+			b: blendChannel(lower.b, upper.b, fraction),
+		}
+	}
+}
+
+// blendChannel interpolates linearly from a to b by fraction in [0, 1]
+func blendChannel(a, b uint8, fraction float64) uint8 {
+	return uint8(math.Round(float64(a) + (float64(b)-float64(a))*fraction))
+}
+
+// rainbowPalette sweeps the hue once around the color wheel
+func rainbowPalette(t float64) rgb {
+	return hsvToRGB(t*360.0, 0.8, 0.9)
+}
+
+// grayscalePalette ramps from black to white
+func grayscalePalette(t float64) rgb {
+	level := channel(t)
+	return rgb{level, level, level}
+}
+
+// firePalette ramps through red, orange and yellow to white, bringing in
+// each channel in turn
+func firePalette(t float64) rgb {
+	return rgb{
+		r: channel(t * 3.0),
+		g: channel(t*3.0 - 1.0),
+		b: channel(t*3.0 - 2.0),
+	}
+}
+
+// oceanPalette ramps from deep blue through teal to pale cyan
+func oceanPalette(t float64) rgb {
+	return rgb{
+		r: channel(t*2.0 - 1.0),
+		g: channel(t * 1.5),
+		b: channel(0.3 + t*0.7),
+	}
+}
+
+// channel converts an intensity to an 8-bit channel, clamping to [0, 1]
+func channel(intensity float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, intensity)) * 255))
+}
+
+// hsvToRGB converts a hue in degrees and saturation/value in [0, 1] to RGB
+func hsvToRGB(h, s, v float64) rgb {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60.0, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return rgb{
+		r: uint8(math.Round((r + m) * 255)),
+		g: uint8(math.Round((g + m) * 255)),
+		b: uint8(math.Round((b + m) * 255)),
+	}
+}
+
+// smoothAt returns the smooth iteration count of a sample displaced from
+// pixel (x, y) by (dx, dy) pixels and whether the point is interior (did not
+// escape within maxIterations)
+func (v viewport) smoothAt(x, y int, dx, dy float64, maxIterations uint32, escapeRadiusSquared float64) (float64, bool) {
+	iterations, zMagnitudeSquared := v.escapeTimeAt(x, y, dx, dy, maxIterations, escapeRadiusSquared)
+	if iterations == maxIterations {
+		return float64(maxIterations), true
+	}
+	return smoothIterations(iterations, zMagnitudeSquared), false
+}
+
+// smoothColor maps a smooth iteration count to a palette color. The palette
+// is traversed once over maxIterations, wrapping for larger counts.
+func smoothColor(smooth float64, maxIterations uint32) rgb {
+	t := smooth / float64(maxIterations)
+	t -= math.Floor(t)
+	return currentPalette(t)
+}
+
+// interiorSmooth marks interior pixels in a buffer of smooth iteration counts
+const interiorSmooth = -1.0
+
+// fillSmooth computes the smooth iteration count of every pixel in row-major
+// order, storing interiorSmooth for points that don't escape
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillSmooth(values []float64, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				smooth, interior := v.smoothAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+				if interior {
+					smooth = interiorSmooth
+				}
+				values[y*v.width+x] = smooth
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
+
+// colorPixels converts smooth iteration counts to RGBA bytes (4 per pixel)
+// using colorFn for escaped points and interiorColor for interior ones
+func colorPixels(pixels []byte, values []float64, colorFn func(smooth float64) rgb) {
+	for i, smooth := range values {
+		color := interiorColor
+		if smooth != interiorSmooth {
+			color = colorFn(smooth)
+		}
+
+		pixels[i*4] = color.r
+		pixels[i*4+1] = color.g
+		pixels[i*4+2] = color.b
+		pixels[i*4+3] = 255
+	}
+}
+
+// fillRGBA renders the viewport as RGBA bytes (4 per pixel, row-major) into
+// pixels, which must hold at least 4*pixelCount bytes, using the current
+// coloring mode
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillRGBA(pixels []byte, maxIterations uint32, escapeRadiusSquared float64) int {
+	values := make([]float64, v.pixelCount())
+	completed := v.fillSmooth(values, maxIterations, escapeRadiusSquared)
+	values = values[:completed]
+
+	colorFn := func(smooth float64) rgb {
+		return smoothColor(smooth, maxIterations)
+	}
+	if coloringMode == histogramColoring {
+		cdf := histogramCDF(smoothHistogram(values, maxIterations))
+		colorFn = func(smooth float64) rgb {
+			return currentPalette(equalize(cdf, smooth))
+		}
+	}
+
+	colorPixels(pixels, values, colorFn)
+	return completed
+}
+
+// renderRGBA renders a viewport of the Mandelbrot set straight to RGBA pixels
+// using the palette selected with setPalette and the coloring mode selected
+// with setColoringMode
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - rgbaBuf: Uint8ClampedArray (or Uint8Array) of at least 4*width*height
+//     bytes, e.g. ImageData.data, receiving RGBA pixels in row-major order
+//   - samplesPerAxis (optional): Supersample each pixel on an N x N grid and
+//     average the colors, for N from 1 to 16; 1 (the default) takes a single
+//     sample per pixel
+//   - pattern (optional): "grid" (the default) for the regular N x N grid, or
+//     "rotated" for 2x2 rotated-grid supersampling, which requires
+//     samplesPerAxis 2
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderRGBA(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderRGBA", args, 8, 9, 10)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	rgbaBuf := r.byteArray(7, "rgbaBuf")
+	r.minLength(rgbaBuf, "rgbaBuf", view.pixelCount()*4)
+
+	samplesPerAxis := 1
+	if r.has(8) {
+		value := r.number(8, "samplesPerAxis")
+		r.check(value >= 1 && value <= maxSamplesPerAxis, "samplesPerAxis must be from 1 to %d, got %v", maxSamplesPerAxis, value)
+		samplesPerAxis = int(value)
+	}
+	pattern := gridPattern
+	if r.has(9) {
+		pattern = r.str(9, "pattern")
+	}
+	r.check(pattern == gridPattern || pattern == rotatedGridPattern, "unknown sample pattern %q", pattern)
+	if pattern == rotatedGridPattern {
+		r.check(samplesPerAxis == 2, "the rotated pattern requires samplesPerAxis 2, got %d", samplesPerAxis)
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	pixels := make([]byte, view.pixelCount()*4)
+
+	var completed int
+	switch {
+	case pattern == rotatedGridPattern:
+		completed = view.fillRGBASupersampled(pixels, rotatedGridOffsets(), maxIterations, escapeThreshold(escapeRadius))
+	case samplesPerAxis == 1:
+		completed = view.fillRGBA(pixels, maxIterations, escapeThreshold(escapeRadius))
+	default:
+		completed = view.fillRGBASupersampled(pixels, gridOffsets(samplesPerAxis), maxIterations, escapeThreshold(escapeRadius))
+	}
+
+	js.CopyBytesToJS(rgbaBuf, pixels[:completed*4])
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
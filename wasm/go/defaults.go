@@ -0,0 +1,81 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Default iteration settings, changed from JavaScript via setDefaults and used
+// by the coordinate-only forms of calculatePoint and calculateMandelbrotSet.
+// They match the defaults of the JS render engine.
+var (
+	defaultMaxIterations uint32 = 256
+	defaultEscapeRadius         = 2.0
+)
+
+// setDefaults stores the maxIterations and escapeRadius used when
+// calculatePoint or calculateMandelbrotSet is called with coordinates only
+//
+// Parameters:
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - true when the defaults were applied, {error} for invalid arguments
+func setDefaults(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setDefaults", args, 2)
+	maxIterations := r.maxIterations(0)
+	escapeRadius := r.escapeRadius(1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	defaultMaxIterations = maxIterations
+	defaultEscapeRadius = escapeRadius
+	return true
+}
+
+// Iteration scaling heuristic for recommendedIterations: at referenceScale,
+// roughly the whole set across an 800 pixel canvas, the base count suffices,
+// and each halving of the pixel scale adds iterationsPerZoomDoubling more
+const (
+	referenceScale            = 3.5 / 800
+	iterationsPerZoomDoubling = 32
+)
+
+// recommendedIterations suggests a maxIterations for a view with the given
+// pixel scale, growing with the zoom depth
+//
+// Deeper zooms resolve finer boundary detail, whose points take longer to
+// escape. The suggestion is base + perDoubling * log2(referenceScale / scale),
+// never less than base: views at or beyond the reference scale get base.
+//
+// Parameters:
+//   - scale: Complex-plane units per pixel, as passed to renderViewport
+//   - base, perDoubling (optional, given together): Count at the reference
+//     scale and iterations added for each doubling of the zoom. They default
+//     to the maxIterations stored by setDefaults and 32.
+//
+// Returns:
+//   - The suggested maxIterations, at most the limit set with
+//     setMaxIterationsLimit, or {error} for invalid arguments
+func recommendedIterations(this js.Value, args []js.Value) interface{} {
+	r := readArgs("recommendedIterations", args, 1, 3)
+	scale := r.number(0, "scale")
+	r.check(scale > 0 && !math.IsInf(scale, 1), "scale must be a positive finite number, got %v", scale)
+	base := defaultMaxIterations
+	perDoubling := float64(iterationsPerZoomDoubling)
+	if r.has(1) {
+		base = r.maxIterations(1)
+		perDoubling = r.number(2, "perDoubling")
+		r.check(perDoubling >= 0 && !math.IsInf(perDoubling, 1), "perDoubling must be a non-negative finite number, got %v", perDoubling)
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	doublings := math.Max(0, math.Log2(referenceScale/scale))
+	return uint32(math.Min(math.Round(float64(base)+perDoubling*doublings), float64(maxIterationsLimit)))
+}
@@ -0,0 +1,126 @@
+//go:build js
+
+package main
+
+import (
+	"encoding/binary"
+	"syscall/js"
+)
+
+// Delta encoding of iteration counts
+//
+// Neighboring pixels usually have equal or nearly equal counts, so storing
+// each count as the difference from the pixel to its left turns a tile into
+// long runs of small numbers. Each difference is written as a zigzag varint
+// (encoding/binary's Varint format): 0 takes one byte, as does any
+// difference from -64 to 63, and no difference takes more than five. The
+// stream is already smaller than the raw Uint32Array and compresses far
+// better with gzip or brotli. Every row starts again from 0, so the first
+// value of a row is its first count.
+
+// appendRowDeltas appends the delta encoding of counts, which holds whole
+// rows of width values, to stream
+func appendRowDeltas(stream []byte, counts []uint32, width int) []byte {
+	var previous uint32
+	for i, count := range counts {
+		if i%width == 0 {
+			previous = 0
+		}
+		stream = binary.AppendVarint(stream, int64(count)-int64(previous))
+		previous = count
+	}
+	return stream
+}
+
+// decodeRowDeltas decodes a delta-encoded stream of rows of width values
+// into counts, which must have room for every value in the stream
+//
+// Returns the number of values decoded and false for a stream that is
+// truncated, holds a count outside the uint32 range or does not fit in counts.
+func decodeRowDeltas(stream []byte, counts []uint32, width int) (int, bool) {
+	written := 0
+	var previous int64
+	for len(stream) > 0 {
+		delta, size := binary.Varint(stream)
+		if size <= 0 || written == len(counts) {
+			return written, false
+		}
+		stream = stream[size:]
+
+		if written%width == 0 {
+			previous = 0
+		}
+		count := previous + delta
+		if count < 0 || count > int64(^uint32(0)) {
+			return written, false
+		}
+		counts[written] = uint32(count)
+		previous = count
+		written++
+	}
+	return written, true
+}
+
+// encodeDeltas delta-encodes iteration counts along rows for compact
+// transmission
+//
+// Parameters:
+//   - countsBuf: Uint32Array of iteration counts in row-major order, a whole
+//     number of rows
+//   - width: Row width in pixels
+//
+// Returns:
+//   - A Uint8Array holding one zigzag varint per count, the difference from
+//     the previous count in the same row, or {error} for invalid arguments
+func encodeDeltas(this js.Value, args []js.Value) interface{} {
+	r := readArgs("encodeDeltas", args, 2)
+	countsBuf := r.typedArray(0, "countsBuf", "Uint32Array")
+	width := r.positiveInteger(1, "width")
+	if r.failed() {
+		return r.errorResult()
+	}
+	r.check(countsBuf.Length()%width == 0, "countsBuf must hold whole rows of %d counts, got %d counts", width, countsBuf.Length())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	stream := appendRowDeltas(nil, readUint32s(countsBuf), width)
+
+	array := js.Global().Get("Uint8Array").New(len(stream))
+	js.CopyBytesToJS(array, stream)
+	return array
+}
+
+// decodeDeltas reverses encodeDeltas, restoring the iteration counts of a
+// delta-encoded stream
+//
+// Parameters:
+//   - deltaBuf: Uint8Array holding a stream produced by encodeDeltas
+//   - width: Row width in pixels, as passed to encodeDeltas
+//   - resultBuf: Uint32Array with room for every count in the stream
+//
+// Returns:
+//   - The number of counts written, or {error} if the arguments are invalid
+//     or the stream is corrupt or does not fit in resultBuf
+func decodeDeltas(this js.Value, args []js.Value) interface{} {
+	r := readArgs("decodeDeltas", args, 3)
+	deltaBuf := r.byteArray(0, "deltaBuf")
+	width := r.positiveInteger(1, "width")
+	resultBuf := r.typedArray(2, "resultBuf", "Uint32Array")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	stream := make([]byte, deltaBuf.Length())
+	js.CopyBytesToGo(stream, deltaBuf)
+
+	counts := make([]uint32, resultBuf.Length())
+	written, ok := decodeRowDeltas(stream, counts, width)
+	r.check(ok, "deltaBuf is corrupt or holds more than the %d counts resultBuf can hold", len(counts))
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	writeUint32s(resultBuf, counts[:written])
+	return written
+}
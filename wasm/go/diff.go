@@ -0,0 +1,105 @@
+//go:build js
+
+package main
+
+import (
+	"bytes"
+	"syscall/js"
+)
+
+// Frame diffing
+//
+// After a small pan or a parameter tweak most of a new frame matches the old
+// one. diffRegion finds the bounding rectangle of the pixels that changed, so
+// the frontend can pass it as the dirty rectangle of putImageData and upload
+// only that part of the canvas.
+
+// diffRegion returns the bounding rectangle of the pixels that differ between
+// two frames
+//
+// Parameters:
+//   - oldBuf: Typed array holding the previous frame in row-major order, such
+//     as a Uint32Array of counts or a Uint8ClampedArray of RGBA pixels
+//   - newBuf: Typed array holding the new frame, with the same byte length
+//   - width, height: Frame dimensions in pixels, at most 268435456 in total;
+//     the byte length must be a whole multiple of width*height, which gives
+//     the bytes per pixel
+//
+// Returns:
+//   - An object {x, y, width, height} bounding every changed pixel, null when
+//     the frames are identical, or {error} for invalid arguments
+func diffRegion(this js.Value, args []js.Value) interface{} {
+	r := readArgs("diffRegion", args, 4)
+	oldBuf := r.anyTypedArray(0, "oldBuf")
+	newBuf := r.anyTypedArray(1, "newBuf")
+	width, height := r.dimensions(2, 3, "width", "height")
+	if r.failed() {
+		return r.errorResult()
+	}
+	byteLength := oldBuf.Get("byteLength").Int()
+	r.check(newBuf.Get("byteLength").Int() == byteLength, "oldBuf and newBuf must have the same byte length, got %d and %d", byteLength, newBuf.Get("byteLength").Int())
+	r.check(byteLength > 0 && byteLength%(width*height) == 0, "buffer byte length %d is not a whole multiple of width*height = %d", byteLength, width*height)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	oldFrame := make([]byte, byteLength)
+	newFrame := make([]byte, byteLength)
+	js.CopyBytesToGo(oldFrame, bytesOf(oldBuf))
+	js.CopyBytesToGo(newFrame, bytesOf(newBuf))
+
+	left, top, right, bottom, changed := changedBounds(oldFrame, newFrame, width, height)
+	if !changed {
+		return js.Null()
+	}
+	return map[string]interface{}{
+		"x":      left,
+		"y":      top,
+		"width":  right - left,
+		"height": bottom - top,
+	}
+}
+
+// changedBounds returns the half-open pixel rectangle [left, right) x
+// [top, bottom) bounding every pixel that differs between two frames of
+// width x height pixels, and whether any pixel differs
+//
+// Unchanged rows are skipped with a single comparison each, and within a
+// changed row only the columns outside the bounds found so far are scanned.
+func changedBounds(oldFrame, newFrame []byte, width, height int) (left, top, right, bottom int, changed bool) {
+	bytesPerPixel := len(oldFrame) / (width * height)
+	rowBytes := width * bytesPerPixel
+	left, right = width, 0
+
+	for y := 0; y < height; y++ {
+		oldRow := oldFrame[y*rowBytes : (y+1)*rowBytes]
+		newRow := newFrame[y*rowBytes : (y+1)*rowBytes]
+		if bytes.Equal(oldRow, newRow) {
+			continue
+		}
+
+		if !changed {
+			top = y
+			changed = true
+		}
+		bottom = y + 1
+
+		pixelDiffers := func(x int) bool {
+			return !bytes.Equal(oldRow[x*bytesPerPixel:(x+1)*bytesPerPixel], newRow[x*bytesPerPixel:(x+1)*bytesPerPixel])
+		}
+		for x := 0; x < left; x++ {
+			if pixelDiffers(x) {
+				left = x
+				break
+			}
+		}
+		for x := width - 1; x >= right; x-- {
+			if pixelDiffers(x) {
+				right = x + 1
+				break
+			}
+		}
+	}
+
+	return left, top, right, bottom, changed
+}
@@ -0,0 +1,80 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Exterior distance estimation
+//
+// Alongside the orbit z_n, the derivative dz_n/dc is iterated as
+// dz_{n+1} = 2*z_n*dz_n + 1. Once z escapes, 2*|z|*ln|z| / |dz| estimates
+// the distance from c to the set: the true distance lies between a quarter
+// of the estimate and the estimate itself. Large escape radii make the
+// estimate more accurate.
+
+// distanceEstimate returns the exterior distance estimate of c and the escape
+// iteration, or 0 and maxIterations for points that don't escape
+func distanceEstimate(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (float64, uint32) {
+	var prevReal, prevImag float64
+	dzReal, dzImag := 0.0, 0.0
+	iterations, zMagnitudeSquared := walkOrbit(cReal, cImag, maxIterations, escapeRadiusSquared, func(zReal, zImag float64) bool {
+		// dz = 2*z*dz + 1, using the z the new value was computed from
+		dzRealTemp := 2*(prevReal*dzReal-prevImag*dzImag) + 1
+		dzImag = 2 * (prevReal*dzImag + prevImag*dzReal)
+		dzReal = dzRealTemp
+		prevReal, prevImag = zReal, zImag
+		return true
+	})
+
+	if iterations == maxIterations {
+		return 0, iterations
+	}
+
+	zMagnitude := math.Sqrt(zMagnitudeSquared)
+	distance := 2 * zMagnitude * math.Log(zMagnitude) / math.Hypot(dzReal, dzImag)
+	return math.Max(0, distance), iterations
+}
+
+// calculateDistanceEstimate estimates the distance from a point to the
+// Mandelbrot set
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - pixelScale (optional): Complex-plane units per pixel. When given, the
+//     distance is returned in pixels and clamped to [0, 1], so it can be
+//     used directly as the alpha of a 1-pixel-wide boundary line.
+//
+// Returns:
+//   - The estimated distance in complex-plane units, or distance/pixelScale
+//     clamped to [0, 1] when pixelScale is given. Points that don't escape
+//     within maxIterations are treated as part of the set and return 0. A
+//     NaN or infinite point escapes at once and returns +Inf, or 1 with
+//     pixelScale. {error} for invalid arguments.
+func calculateDistanceEstimate(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateDistanceEstimate", args, 4, 5)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	normalize := r.has(4)
+	pixelScale := 0.0
+	if normalize {
+		pixelScale = r.number(4, "pixelScale")
+		r.check(pixelScale > 0, "pixelScale must be greater than 0, got %v", pixelScale)
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	distance, _ := distanceEstimate(real, imag, maxIterations, escapeThreshold(escapeRadius))
+	if normalize {
+		return math.Min(1, distance/pixelScale)
+	}
+	return distance
+}
@@ -0,0 +1,138 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// highPrecision selects double-double arithmetic for viewport renders,
+// changed from JavaScript via setHighPrecision. The float64 path is the
+// default because double-double iteration is several times slower.
+var highPrecision = false
+
+// setHighPrecision enables or disables double-double arithmetic for the
+// viewport renderers (renderViewport, renderToMemory and renderRGBA)
+//
+// Parameters:
+//   - enabled: When true, pixel coordinates and the iteration loop use
+//     double-double arithmetic (roughly 32 significant digits)
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setHighPrecision(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setHighPrecision", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	highPrecision = r.value(0).Truthy()
+	return true
+}
+
+// doubleDouble is an unevaluated sum hi + lo of two float64s with
+// |lo| <= ulp(hi)/2, giving about 106 bits of significand
+type doubleDouble struct {
+	hi float64
+	lo float64
+}
+
+// splitter is 2^27 + 1, used by Dekker's split to cut a float64 significand
+// into two 26-bit halves whose products are exact
+const splitter = 134217729.0
+
+// twoSum returns s = fl(a + b) and the exact rounding error e, so that
+// a + b == s + e (Knuth)
+func twoSum(a, b float64) (float64, float64) {
+	s := a + b
+	bb := s - a
+	e := (a - (s - bb)) + (b - bb)
+	return s, e
+}
+
+// quickTwoSum is twoSum for |a| >= |b|, which needs fewer operations
+func quickTwoSum(a, b float64) (float64, float64) {
+	s := a + b
+	e := b - (s - a)
+	return s, e
+}
+
+// split divides a into high and low halves with a == hi + lo, each fitting
+// in 26 bits (Dekker)
+func split(a float64) (float64, float64) {
+	t := splitter * a
+	hi := t - (t - a)
+	lo := a - hi
+	return hi, lo
+}
+
+// twoProduct returns p = fl(a * b) and the exact rounding error e, so that
+// a * b == p + e (Dekker)
+func twoProduct(a, b float64) (float64, float64) {
+	p := a * b
+	aHi, aLo := split(a)
+	bHi, bLo := split(b)
+	e := ((aHi*bHi - p) + aHi*bLo + aLo*bHi) + aLo*bLo
+	return p, e
+}
+
+// ddFromSum returns the exact sum of two float64s as a double-double
+func ddFromSum(a, b float64) doubleDouble {
+	s, e := twoSum(a, b)
+	return doubleDouble{s, e}
+}
+
+// add returns a + b
+func (a doubleDouble) add(b doubleDouble) doubleDouble {
+	s, e := twoSum(a.hi, b.hi)
+	t, f := twoSum(a.lo, b.lo)
+	e += t
+	s, e = quickTwoSum(s, e)
+	e += f
+	s, e = quickTwoSum(s, e)
+	return doubleDouble{s, e}
+}
+
+// sub returns a - b
+func (a doubleDouble) sub(b doubleDouble) doubleDouble {
+	return a.add(doubleDouble{-b.hi, -b.lo})
+}
+
+// mul returns a * b
+func (a doubleDouble) mul(b doubleDouble) doubleDouble {
+	p, e := twoProduct(a.hi, b.hi)
+	e += a.hi*b.lo + a.lo*b.hi
+	p, e = quickTwoSum(p, e)
+	return doubleDouble{p, e}
+}
+
+// double returns 2a, which is exact
+func (a doubleDouble) double() doubleDouble {
+	return doubleDouble{2 * a.hi, 2 * a.lo}
+}
+
+// escapeTimeDD is escapeTime for z0 = 0 with c and the orbit held in
+// double-double precision
+//
+// The escape test only needs the leading parts, so it is done in float64.
+// Returns the iteration count and |z|^2 at that point, as escapeTime does.
+func escapeTimeDD(cReal, cImag doubleDouble, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	var zReal, zImag doubleDouble
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		zRealSquared := zReal.mul(zReal)
+		zImagSquared := zImag.mul(zImag)
+
+		magnitudeSquared := zRealSquared.hi + zImagSquared.hi
+		if magnitudeSquared > escapeRadiusSquared {
+			return iteration, magnitudeSquared
+		}
+
+		// z = z^2 + c
+		zImag = zReal.mul(zImag).double().add(cImag)
+		zReal = zRealSquared.sub(zImagSquared).add(cReal)
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal.hi*zReal.hi + zImag.hi*zImag.hi
+}
@@ -0,0 +1,91 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Exponential map projection
+//
+// The exponential map unrolls the plane around a center into a strip: pixel
+// column x is a log-radius and row y an angle, so
+//
+//	c = center + baseRadius * exp(2*pi*(x + i*y) / height)
+//
+// The rows cover one full turn and horizontal and vertical pixel steps have
+// the same length in the log-polar plane, so shapes keep their proportions.
+// Each column zooms out by a factor of e^(2*pi/height) over the previous one,
+// which makes a wide strip a record of an entire zoom: successive frames of a
+// zoom animation are slices of it.
+
+// exponentialMapPoint returns the complex coordinate of pixel (x, y) of an
+// exponential map strip of the given height
+func exponentialMapPoint(x, y, height int, centerReal, centerImag, baseRadius float64) (float64, float64) {
+	step := 2 * math.Pi / float64(height)
+	radius := baseRadius * math.Exp(float64(x)*step)
+	sin, cos := math.Sincos(float64(y) * step)
+	return centerReal + radius*cos, centerImag + radius*sin
+}
+
+// renderExponentialMap renders the Mandelbrot set in the exponential map
+// projection around a center point
+//
+// Parameters:
+//   - width: Strip width in pixels; column x has radius
+//     baseRadius*exp(2*pi*x/height)
+//   - height: Strip height in pixels, covering one full turn; row y has angle
+//     2*pi*y/height, counterclockwise from the positive real axis. The strip
+//     covers at most 268435456 pixels, as for the viewport renderers.
+//   - centerReal: Real component of the zoom center
+//   - centerImag: Imaginary component of the zoom center
+//   - baseRadius: Distance from the center sampled by column 0, greater than 0
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderExponentialMap(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderExponentialMap", args, 8)
+	width, height := r.dimensions(0, 1, "width", "height")
+	centerReal := r.number(2, "centerReal")
+	centerImag := r.number(3, "centerImag")
+	baseRadius := r.number(4, "baseRadius")
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	resultBuf := r.typedArray(7, "resultBuf", "Uint32Array")
+	r.check(baseRadius > 0, "baseRadius must be greater than 0, got %v", baseRadius)
+	r.minLength(resultBuf, "resultBuf", width*height)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	beginRender()
+	results := make([]uint32, width*height)
+	completedRows := parallelFor(height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < width; x++ {
+				cReal, cImag := exponentialMapPoint(x, y, height, centerReal, centerImag, baseRadius)
+				results[y*width+x] = Iterate(cReal, cImag, maxIterations, escapeRadiusSquared)
+			}
+		}
+		return endRow - startRow
+	})
+
+	completed := completedRows * width
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,65 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFixedMul checks Q4.60 multiplication against exact products of values
+// whose products are representable
+func TestFixedMul(t *testing.T) {
+	tests := []struct {
+		a, b, want float64
+	}{
+		{1, 1, 1},
+		{1.5, 2, 3},
+		{-1.5, 2, -3},
+		{-0.25, -0.5, 0.125},
+		{0, -3.75, 0},
+		{2, 2, 4},
+	}
+
+	for _, tt := range tests {
+		if got := fromFixed(fixedMul(toFixed(tt.a), toFixed(tt.b))); got != tt.want {
+			t.Errorf("fixedMul(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestEscapeTimeFixedMatchesFloat compares fixed-point escape times with the
+// float64 loop on a grid over the set, where they may only differ on the few
+// points whose orbits are so close to the escape radius that rounding decides
+func TestEscapeTimeFixedMatchesFloat(t *testing.T) {
+	realCoords, imagCoords := gridPoints(97, 61)
+	const maxIterations = 200
+
+	mismatches := 0
+	for i := range realCoords {
+		want, _ := escapeTime(0, 0, realCoords[i], imagCoords[i], maxIterations, 4)
+		got, _ := escapeTimeFixed(realCoords[i], imagCoords[i], maxIterations, 4)
+		if got != want {
+			mismatches++
+		}
+	}
+	if limit := len(realCoords) / 100; mismatches > limit {
+		t.Errorf("%d of %d points differ from the float64 loop, want at most %d", mismatches, len(realCoords), limit)
+	}
+}
+
+// TestEscapeTimeFixedLargeInputs checks the shortcuts that keep Q4.60 values
+// in range: components of c beyond 2 and escape radii beyond 2
+func TestEscapeTimeFixedLargeInputs(t *testing.T) {
+	if got, magnitudeSquared := escapeTimeFixed(100, -50, 10, 4); got != 1 || magnitudeSquared != 12500 {
+		t.Errorf("escapeTimeFixed(100, -50) = %d, %v, want 1, 12500", got, magnitudeSquared)
+	}
+
+	// c = -2 is on the boundary with the bounded orbit 0, -2, 2, 2, ...
+	if got, _ := escapeTimeFixed(-2, 0, 50, 1e6); got != 50 {
+		t.Errorf("escapeTimeFixed(-2, 0) with a large radius = %d, want 50", got)
+	}
+	if got, _ := escapeTimeFixed(0.3, 0.6, 1000, math.Inf(1)); got >= 1000 {
+		t.Errorf("escapeTimeFixed(0.3, 0.6) with an infinite radius did not escape")
+	}
+}
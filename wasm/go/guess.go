@@ -0,0 +1,115 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Neighbor guessing
+//
+// The pixels with even x and even y are iterated first. Each 3x3
+// neighborhood whose four corners are such pixels is then checked: if the
+// corners agree, the center is iterated as a verification sample, and when it
+// agrees too, the edge midpoints above and to the left of the center take the
+// shared count without iterating. Any other pixel is iterated as usual.
+//
+// In smooth regions this skips half of the pixels. It is a heuristic: a
+// filament thinner than two pixels that passes between the samples of a
+// uniform neighborhood is filled over.
+
+// neighborGuessing selects neighbor guessing for row-major viewport renders,
+// changed from JavaScript via setNeighborGuessing
+var neighborGuessing = false
+
+// setNeighborGuessing enables or disables neighbor guessing for the
+// row-major viewport renderers (renderViewport, renderToMemory and renderTile)
+//
+// Parameters:
+//   - enabled: When true, pixels enclosed by a uniform 3x3 neighborhood copy
+//     its count instead of being iterated
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setNeighborGuessing(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setNeighborGuessing", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	neighborGuessing = r.value(0).Truthy()
+	return true
+}
+
+// fillGuessed is fillEscapeTimes in row-major order using neighbor guessing
+//
+// Rows are split into one band per worker and neighborhoods are only guessed
+// when they lie entirely inside a band, so workers never read pixels another
+// worker is writing. Progress is reported as rows are finished, after each
+// band's even grid.
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillGuessed(results []uint32, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	done := make([]bool, v.pixelCount())
+	pixel := func(x, y int) uint32 {
+		index := y*v.width + x
+		if !done[index] {
+			results[index] = v.countAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+			done[index] = true
+		}
+		return results[index]
+	}
+
+	renderProgress.begin(v.height)
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		// Iterate the even grid, which every guess starts from
+		firstEvenRow := startRow + startRow%2
+		for y := firstEvenRow; y < endRow; y += 2 {
+			if (y-firstEvenRow)%(2*rowsPerCancelCheck) == 0 && isRenderCancelled() {
+				return 0
+			}
+			for x := 0; x < v.width; x += 2 {
+				pixel(x, y)
+			}
+		}
+
+		// Guess the midpoints of uniform neighborhoods, then iterate whatever
+		// is left row by row
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			if y%2 == 0 && y+2 < endRow {
+				for x := 0; x+2 < v.width; x += 2 {
+					corner := pixel(x, y)
+					if pixel(x+2, y) != corner || pixel(x, y+2) != corner || pixel(x+2, y+2) != corner {
+						continue
+					}
+					if pixel(x+1, y+1) != corner {
+						continue
+					}
+
+					for _, index := range [2]int{y*v.width + x + 1, (y+1)*v.width + x} {
+						if !done[index] {
+							results[index] = corner
+							done[index] = true
+						}
+					}
+				}
+			}
+
+			for x := 0; x < v.width; x++ {
+				pixel(x, y)
+			}
+			renderProgress.rowDone()
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
@@ -0,0 +1,37 @@
+//go:build js
+
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"syscall/js"
+)
+
+// hashBuffer returns a 64-bit FNV-1a hash of a typed array's contents, for
+// use as a cache key or for detecting changes in render output
+//
+// The hash covers the bytes of the array in memory order (little-endian for
+// multi-byte elements), so arrays of different types holding the same bytes
+// hash alike. It is not cryptographic.
+//
+// Parameters:
+//   - buf: Typed array to hash, such as a Uint32Array of iteration counts
+//
+// Returns:
+//   - The hash as 16 lowercase hexadecimal digits, since 64 bits don't fit
+//     in a JS number exactly, or {error} for invalid arguments
+func hashBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("hashBuffer", args, 1)
+	buf := r.anyTypedArray(0, "buf")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	data := make([]byte, buf.Get("byteLength").Int())
+	js.CopyBytesToGo(data, bytesOf(buf))
+
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
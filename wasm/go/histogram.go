@@ -0,0 +1,137 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Histogram equalization coloring
+//
+// Instead of mapping iteration n to n/maxIterations, each escaped pixel is
+// colored by the fraction of escaped pixels that escaped no later than it did.
+// Palette colors are then spread by pixel population, so the few slow-escaping
+// pixels near the boundary no longer consume most of the palette.
+
+// Coloring modes accepted by setColoringMode
+const (
+	linearColoring    = "linear"
+	histogramColoring = "histogram"
+)
+
+// coloringMode is the mode renderRGBA uses
+var coloringMode = linearColoring
+
+// setColoringMode selects how renderRGBA maps iteration counts to palette positions
+//
+// Parameters:
+//   - mode: "linear" (smooth count / maxIterations, the default) or
+//     "histogram" (histogram equalization over the rendered frame)
+//
+// Returns:
+//   - true when the mode was selected, {error} for unknown modes
+func setColoringMode(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setColoringMode", args, 1)
+	mode := r.str(0, "mode")
+	r.check(mode == linearColoring || mode == histogramColoring, "unknown coloring mode %q", mode)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	coloringMode = mode
+	return true
+}
+
+// computeHistogram builds the cumulative distribution of escape iterations
+// for an iteration buffer
+//
+// Parameters:
+//   - iterationBuf: Uint32Array (or array) of iteration counts, e.g. from renderViewport
+//   - maxIterations: The maxIterations the buffer was rendered with; these
+//     interior points are left out of the histogram
+//
+// Returns:
+//   - Float64Array of length maxIterations where entry n is the fraction of
+//     escaped pixels whose iteration count is n or less. All zeros when no
+//     pixel escaped. {error} for invalid arguments.
+func computeHistogram(this js.Value, args []js.Value) interface{} {
+	r := readArgs("computeHistogram", args, 2)
+	iterationBuf := r.array(0, "iterationBuf")
+	maxIterations := r.maxIterations(1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations := readUint32s(iterationBuf)
+
+	counts := make([]uint64, maxIterations)
+	for _, n := range iterations {
+		if n < maxIterations {
+			counts[n]++
+		}
+	}
+
+	return newFloat64Array(histogramCDF(counts))
+}
+
+// smoothHistogram counts escaped pixels by the integer part of their smooth
+// iteration count, clamped to [0, maxIterations)
+func smoothHistogram(values []float64, maxIterations uint32) []uint64 {
+	counts := make([]uint64, maxIterations)
+	for _, smooth := range values {
+		if smooth == interiorSmooth {
+			continue
+		}
+		counts[histogramBin(smooth, len(counts))]++
+	}
+	return counts
+}
+
+// histogramBin returns the histogram bin for a smooth iteration count
+func histogramBin(smooth float64, bins int) int {
+	n := int(math.Floor(smooth))
+	if n < 0 {
+		return 0
+	}
+	if n >= bins {
+		return bins - 1
+	}
+	return n
+}
+
+// histogramCDF converts per-iteration counts into a cumulative distribution,
+// where entry n is the fraction of all counted pixels in bins 0..n
+func histogramCDF(counts []uint64) []float64 {
+	total := uint64(0)
+	for _, count := range counts {
+		total += count
+	}
+
+	cdf := make([]float64, len(counts))
+	if total == 0 {
+		return cdf
+	}
+
+	running := uint64(0)
+	for n, count := range counts {
+		running += count
+		cdf[n] = float64(running) / float64(total)
+	}
+	return cdf
+}
+
+// equalize maps a smooth iteration count to a palette position using the
+// cumulative distribution, interpolating within the bin by the fractional
+// part so smooth coloring is kept
+func equalize(cdf []float64, smooth float64) float64 {
+	n := histogramBin(smooth, len(cdf))
+
+	low := 0.0
+	if n > 0 {
+		low = cdf[n-1]
+	}
+
+	fraction := math.Max(0, math.Min(1, smooth-float64(n)))
+	return low + fraction*(cdf[n]-low)
+}
@@ -0,0 +1,57 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Inclusive escape
+//
+// By default a point escapes at the first iteration n with
+// |z_n|^2 > escapeRadius^2, so an orbit value exactly on the escape circle
+// has not escaped yet. Some reference renderers test |z_n|^2 >= escapeRadius^2
+// instead, which reports one iteration earlier for orbits that land exactly on
+// the circle, such as c = 2 at radius 2. setInclusiveEscape(true) switches to
+// that test.
+//
+// The escape loops keep their strict comparison. For float64 values,
+// x >= r is the same test as x > nextDown(r), the largest float64 below r, so
+// escapeThreshold lowers the squared radius by one ulp instead. That makes
+// the float64 and double-double paths match a >= test bit for bit, and the
+// square bailout along with them at radius 2. Fixed-point arithmetic rounds
+// the threshold to its own format and keeps the strict test.
+
+// inclusiveEscape makes points on the escape circle count as escaped, changed
+// from JavaScript via setInclusiveEscape
+var inclusiveEscape = false
+
+// setInclusiveEscape selects whether the escape test is |z|^2 >= R^2 instead
+// of the default |z|^2 > R^2
+//
+// Parameters:
+//   - enabled: When true, orbit values exactly on the escape circle escape
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setInclusiveEscape(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setInclusiveEscape", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	inclusiveEscape = r.value(0).Truthy()
+	return true
+}
+
+// escapeThreshold returns the squared magnitude that |z|^2 must exceed for a
+// point to escape with the given escape radius: escapeRadius^2, or the
+// float64 just below it while inclusiveEscape is set
+func escapeThreshold(escapeRadius float64) float64 {
+	escapeRadiusSquared := escapeRadius * escapeRadius
+	if inclusiveEscape {
+		return math.Nextafter(escapeRadiusSquared, 0)
+	}
+	return escapeRadiusSquared
+}
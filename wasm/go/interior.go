@@ -0,0 +1,118 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Interior value
+//
+// A point can reach maxIterations in two ways: it is proven interior, by
+// the cardioid and bulb test or by periodicity checking catching a cycle, or
+// it merely ran out of iterations and may still escape later. By default both
+// report maxIterations. After setInteriorValue(v) the iteration counts of
+// calculatePoint, the Mandelbrot batch functions and the viewport renderers
+// report proven interior points as v instead, so a palette can tell them
+// apart from points near the boundary.
+//
+// Only counts are affected. Functions that color, smooth or otherwise
+// interpret counts still see maxIterations for every interior point.
+
+// interiorValue is the count reported for proven interior points while
+// interiorValueSet is true, changed from JavaScript via setInteriorValue
+var (
+	interiorValue    uint32
+	interiorValueSet = false
+)
+
+// setInteriorValue sets the iteration count reported for points proven to
+// be interior, or restores the default of maxIterations
+//
+// Parameters:
+//   - value (optional): Count to report, an integer from 0 to 4294967295.
+//     null, undefined or no argument reports maxIterations again.
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setInteriorValue(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setInteriorValue", args, 0, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+	if !r.has(0) || r.value(0).IsNull() || r.value(0).IsUndefined() {
+		interiorValueSet = false
+		return true
+	}
+	value := r.number(0, "value")
+	r.check(value >= 0 && value <= math.MaxUint32 && value == math.Trunc(value), "value must be an integer from 0 to %d, got %v", uint32(math.MaxUint32), value)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	interiorValue = uint32(value)
+	interiorValueSet = true
+	return true
+}
+
+// reportedCount returns the count to report for a point: iterations, or
+// interiorValue for a point proven interior while one is set
+func reportedCount(iterations uint32, proven bool) uint32 {
+	if proven && interiorValueSet {
+		return interiorValue
+	}
+	return iterations
+}
+
+// markProvenInterior makes reportedCount report proven interior points as
+// maxIterations+1 until the returned restore is called, for functions that
+// must tell interior points apart from escaped ones: an interior value below
+// maxIterations would otherwise look like an escape count. While marking,
+// every count at or above maxIterations is an interior point. restore
+// replaces the marker in results with the interior value and ends the
+// marking.
+//
+// maxIterations of 4294967295 leaves no count above it, so there proven
+// points are reported as maxIterations instead.
+func markProvenInterior(maxIterations uint32) (restore func(results []uint32)) {
+	if !interiorValueSet {
+		return func([]uint32) {}
+	}
+	if maxIterations == math.MaxUint32 {
+		interiorValueSet = false
+		return func([]uint32) { interiorValueSet = true }
+	}
+
+	saved, marker := interiorValue, maxIterations+1
+	interiorValue = marker
+	return func(results []uint32) {
+		interiorValue = saved
+		for i, iterations := range results {
+			if iterations == marker {
+				results[i] = saved
+			}
+		}
+	}
+}
+
+// escapeTimeProven is escapeTime also reporting whether the point was proven
+// interior by periodicity checking
+func escapeTimeProven(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64, bool) {
+	if periodicityCheck && bailoutShape != squareBailout && isFinite(zReal, zImag) && isFinite(cReal, cImag) {
+		return periodicEscapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	}
+	iterations, zMagnitudeSquared := escapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	return iterations, zMagnitudeSquared, false
+}
+
+// iterateReported is Iterate with proven interior points reported as
+// interiorValue when one is set
+func iterateReported(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) uint32 {
+	if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag) {
+		return reportedCount(maxIterations, true)
+	}
+
+	iterations, _, proven := escapeTimeProven(0, 0, cReal, cImag, maxIterations, escapeRadiusSquared)
+	return reportedCount(iterations, proven)
+}
@@ -0,0 +1,46 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// setCheckInterval sets how many iterations the scalar escape-time loop runs
+// between escape tests, the default for calls without a checkInterval
+// argument
+//
+// Parameters:
+//   - interval: Iterations per escape test, at least 1. 1 (the default) tests
+//     after every iteration.
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setCheckInterval(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setCheckInterval", args, 1)
+	interval := r.positiveInteger(0, "interval")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	checkInterval = interval
+	return true
+}
+
+// checkInterval returns the optional check interval argument at index, or
+// the interval set with setCheckInterval when it is omitted, null or
+// undefined
+func (r *argReader) checkInterval(index int) int {
+	if !r.has(index) || r.value(index).IsNull() || r.value(index).IsUndefined() {
+		return checkInterval
+	}
+	return r.positiveInteger(index, "checkInterval")
+}
+
+// useCheckInterval sets interval for the rest of a call, returning a
+// function that restores the interval set before
+func useCheckInterval(interval int) (restore func()) {
+	saved := checkInterval
+	checkInterval = interval
+	return func() { checkInterval = saved }
+}
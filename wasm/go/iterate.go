@@ -0,0 +1,184 @@
+package main
+
+import (
+	"math"
+)
+
+// Iteration core
+//
+// Iterate, IterateJulia and IterateMultibrot are the escape-time counts the
+// JS wrappers are built on, as plain Go functions that take and return no
+// js.Value, so they can be tested with go test and reused by other Go code
+// in this package. They follow the package-level settings that the JS
+// setters change (periodicity checking, bailout shape and check interval),
+// which are off until a setter is called.
+//
+// This file and the settings it follows carry no build constraint, while
+// the JS wrappers are built only with //go:build js, so the core also builds
+// and tests natively.
+
+// Iterate returns the Mandelbrot escape time of c = cReal + cImag*i: the
+// iteration at which |z|^2 of z = z^2 + c, starting from zero, first exceeds
+// escapeRadiusSquared, or maxIterations if it never does
+//
+// Points in the main cardioid or period-2 bulb return maxIterations without
+// iterating when the escape radius is at least 2.
+func Iterate(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) uint32 {
+	if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag) {
+		return maxIterations
+	}
+
+	iterations, _ := escapeTime(0, 0, cReal, cImag, maxIterations, escapeRadiusSquared)
+	return iterations
+}
+
+// IterateJulia returns the escape time of z = zReal + zImag*i in the Julia
+// set of c = cReal + cImag*i, iterating z = z^2 + c from z itself
+func IterateJulia(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) uint32 {
+	iterations, _ := escapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	return iterations
+}
+
+// IterateMultibrot returns the escape time of c in the multibrot set of the
+// given power, iterating z = z^power + c from zero; power must be at least 2
+//
+// Power 2 gives the same counts as escapeTime without the cardioid and bulb
+// shortcut, which only holds for the quadratic set.
+func IterateMultibrot(cReal, cImag float64, power int, maxIterations uint32, escapeRadiusSquared float64) uint32 {
+	iterations, _ := multibrotEscapeTime(0, 0, cReal, cImag, power, maxIterations, escapeRadiusSquared)
+	return iterations
+}
+
+// escapeTime iterates z = z^2 + c starting from z = zReal + zImag*i
+//
+// The Mandelbrot set starts every orbit at zero and takes c from the point
+// being tested; the Julia set starts at the point and keeps c fixed.
+//
+// Returns the iteration at which |z|^2 exceeded escapeRadiusSquared together
+// with |z|^2 at that moment. Points that don't escape return maxIterations and
+// the squared magnitude from the last iteration.
+//
+// When periodicity checking is enabled the orbit is handed to
+// escapeTimePeriodic, which may stop early for periodic orbits. The square
+// bailout selected with setBailoutShape hands it to escapeTimeSquare instead,
+// without periodicity checking. Otherwise a check interval set with
+// setCheckInterval hands it to escapeTimeInterval where that is exact.
+//
+// A NaN or infinite z or c escapes at iteration 0 with an infinite magnitude
+// instead of iterating: NaN never compares greater than the radius, so such
+// an orbit would otherwise run to maxIterations and pass for interior.
+func escapeTime(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	if !isFinite(zReal, zImag) || !isFinite(cReal, cImag) {
+		return 0, math.Inf(1)
+	}
+	if bailoutShape == squareBailout {
+		return escapeTimeSquare(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	}
+	if periodicityCheck {
+		return escapeTimePeriodic(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	}
+	if checkInterval > 1 && escapeRadiusSquared >= 4.0 && cReal*cReal+cImag*cImag <= escapeRadiusSquared {
+		return escapeTimeInterval(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared, checkInterval)
+	}
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared
+		}
+
+		// Calculate z = z^2 + c
+		// (a + bi)^2 = a^2 - b^2 + 2abi
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
+
+// isFinite reports whether both components of a complex number are neither
+// NaN nor infinite
+func isFinite(real, imag float64) bool {
+	return !math.IsNaN(real) && !math.IsInf(real, 0) && !math.IsNaN(imag) && !math.IsInf(imag, 0)
+}
+
+// interiorShortcutRadiusSquared is the smallest escape threshold (see
+// escapeThreshold) for which inCardioidOrBulb may be used: the float64 just
+// below 4, so that inclusive escape at radius 2 keeps the shortcut. Orbits
+// inside the cardioid and bulb never reach |z| = 2, so they can't escape
+// against either 4 or this threshold.
+var interiorShortcutRadiusSquared = math.Nextafter(4, 0)
+
+// inCardioidOrBulb reports whether c lies inside the main cardioid or the
+// period-2 bulb, where orbits are known never to escape
+//
+// Every orbit of a point in the Mandelbrot set stays within |z| <= 2, so the
+// shortcut only gives the same answer as iterating when the escape radius is
+// at least 2; callers must check the threshold against
+// interiorShortcutRadiusSquared before using it. Infinite c would pass the
+// cardioid test and is reported as outside, like NaN.
+func inCardioidOrBulb(cReal, cImag float64) bool {
+	if !isFinite(cReal, cImag) {
+		return false
+	}
+
+	// Main cardioid: q*(q + (x - 1/4)) <= y^2/4 with q = (x - 1/4)^2 + y^2
+	cImagSquared := cImag * cImag
+	xShifted := cReal - 0.25
+	q := xShifted*xShifted + cImagSquared
+	if q*(q+xShifted) <= 0.25*cImagSquared {
+		return true
+	}
+
+	// Period-2 bulb: circle of radius 1/4 centered at -1
+	xBulb := cReal + 1.0
+	return xBulb*xBulb+cImagSquared <= 0.0625
+}
+
+// multibrotEscapeTime iterates z = z^power + c starting from z = zReal + zImag*i
+//
+// Power 2 is delegated to escapeTime so that it is numerically identical to the
+// quadratic Mandelbrot and Julia functions. Returns the same values as escapeTime.
+func multibrotEscapeTime(zReal, zImag, cReal, cImag float64, power int, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	if power == 2 {
+		return escapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	}
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared
+		}
+
+		// Calculate z = z^power + c
+		zReal, zImag = complexPow(zReal, zImag, power)
+		zReal += cReal
+		zImag += cImag
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
+
+// complexPow raises a + bi to a positive integer power by repeated
+// multiplication, which avoids the trig and log calls of a polar pow and is
+// faster for the small exponents multibrot sets use
+func complexPow(real, imag float64, power int) (float64, float64) {
+	resultReal := real
+	resultImag := imag
+
+	for i := 1; i < power; i++ {
+		// (x + yi)(a + bi) = xa - yb + (xb + ya)i
+		resultReal, resultImag = resultReal*real-resultImag*imag, resultReal*imag+resultImag*real
+	}
+
+	return resultReal, resultImag
+}
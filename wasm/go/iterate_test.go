@@ -0,0 +1,168 @@
+package main
+
+import (
+	"testing"
+)
+
+// gridPoints returns the points of a width x height grid spanning real
+// [-2.5, 1] and imag [-1.5, 1.5], edges included
+func gridPoints(width, height int) ([]float64, []float64) {
+	realCoords := make([]float64, 0, width*height)
+	imagCoords := make([]float64, 0, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			realCoords = append(realCoords, -2.5+3.5*float64(x)/float64(width-1))
+			imagCoords = append(imagCoords, -1.5+3.0*float64(y)/float64(height-1))
+		}
+	}
+	return realCoords, imagCoords
+}
+
+// TestIterate checks Mandelbrot escape times of points whose orbits can be
+// followed by hand
+func TestIterate(t *testing.T) {
+	tests := []struct {
+		name          string
+		cReal, cImag  float64
+		maxIterations uint32
+		want          uint32
+	}{
+		{"origin is interior", 0, 0, 100, 100},
+		{"period-2 center is interior", -1, 0, 100, 100},
+		{"tip stays on the escape radius", -2, 0, 100, 100},
+		{"i is preperiodic", 0, 1, 100, 100},
+		{"2 reaches the radius without exceeding it", 2, 0, 100, 2},
+		{"3 escapes after one step", 3, 0, 100, 1},
+		{"1 escapes at 0, 1, 2, 5", 1, 0, 100, 3},
+		{"limit caps the count", 1, 0, 2, 2},
+		{"zero iterations", 3, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Iterate(tt.cReal, tt.cImag, tt.maxIterations, 4); got != tt.want {
+				t.Errorf("Iterate(%v, %v, %d, 4) = %d, want %d", tt.cReal, tt.cImag, tt.maxIterations, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIterateJulia checks Julia escape times for c = 0, whose orbits are
+// z, z^2, z^4, ..., and for c = -1
+func TestIterateJulia(t *testing.T) {
+	tests := []struct {
+		name         string
+		zReal, zImag float64
+		cReal, cImag float64
+		want         uint32
+	}{
+		{"inside the unit circle", 0.5, 0, 0, 0, 100},
+		{"on the unit circle", 0, 1, 0, 0, 100},
+		{"1.5 escapes after one step", 1.5, 0, 0, 0, 1},
+		{"starts outside the radius", 3, 0, 0, 0, 0},
+		{"basilica center", 0, 0, -1, 0, 100},
+		{"basilica exterior escapes at 2, 3", 2, 0, -1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IterateJulia(tt.zReal, tt.zImag, tt.cReal, tt.cImag, 100, 4); got != tt.want {
+				t.Errorf("IterateJulia(%v, %v, %v, %v, 100, 4) = %d, want %d", tt.zReal, tt.zImag, tt.cReal, tt.cImag, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIterateMultibrot checks multibrot escape times for known orbits and
+// that power 2 matches Iterate
+func TestIterateMultibrot(t *testing.T) {
+	tests := []struct {
+		name         string
+		cReal, cImag float64
+		power        int
+		want         uint32
+	}{
+		{"cubic origin is interior", 0, 0, 3, 100},
+		{"cubic 1 escapes at 0, 1, 2, 9", 1, 0, 3, 3},
+		{"cubic -1 escapes at 0, -1, -2, -9", -1, 0, 3, 3},
+		{"quartic 1 escapes at 0, 1, 2, 17", 1, 0, 4, 3},
+		{"quadratic matches Iterate", 1, 0, 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IterateMultibrot(tt.cReal, tt.cImag, tt.power, 100, 4); got != tt.want {
+				t.Errorf("IterateMultibrot(%v, %v, %d, 100, 4) = %d, want %d", tt.cReal, tt.cImag, tt.power, got, tt.want)
+			}
+		})
+	}
+
+	realCoords, imagCoords := gridPoints(41, 31)
+	for i := range realCoords {
+		want, _ := escapeTime(0, 0, realCoords[i], imagCoords[i], 100, 4)
+		if got := IterateMultibrot(realCoords[i], imagCoords[i], 2, 100, 4); got != want {
+			t.Errorf("IterateMultibrot(%v, %v, 2, 100, 4) = %d, want %d", realCoords[i], imagCoords[i], got, want)
+		}
+	}
+}
+
+// TestCheckIntervalMatchesEveryIterationCheck checks that interval escape
+// tests give the same counts and magnitudes as testing every iteration, for
+// Mandelbrot and Julia orbits
+func TestCheckIntervalMatchesEveryIterationCheck(t *testing.T) {
+	realCoords, imagCoords := gridPoints(97, 61)
+	defer func(saved int) { checkInterval = saved }(checkInterval)
+
+	for _, interval := range []int{2, 3, 8, 64} {
+		for _, escapeRadius := range []float64{2, 1e3} {
+			for i := range realCoords {
+				escapeRadiusSquared := escapeRadius * escapeRadius
+				checkInterval = 1
+				wantMandelbrot, wantMandelbrotMagnitude := escapeTime(0, 0, realCoords[i], imagCoords[i], 300, escapeRadiusSquared)
+				wantJulia, wantJuliaMagnitude := escapeTime(realCoords[i], imagCoords[i], -0.8, 0.156, 300, escapeRadiusSquared)
+
+				checkInterval = interval
+				gotMandelbrot, gotMandelbrotMagnitude := escapeTime(0, 0, realCoords[i], imagCoords[i], 300, escapeRadiusSquared)
+				gotJulia, gotJuliaMagnitude := escapeTime(realCoords[i], imagCoords[i], -0.8, 0.156, 300, escapeRadiusSquared)
+
+				if gotMandelbrot != wantMandelbrot || gotMandelbrotMagnitude != wantMandelbrotMagnitude {
+					t.Fatalf("interval %d, radius %v, c = (%v, %v): got (%d, %v), want (%d, %v)", interval, escapeRadius, realCoords[i], imagCoords[i], gotMandelbrot, gotMandelbrotMagnitude, wantMandelbrot, wantMandelbrotMagnitude)
+				}
+				if gotJulia != wantJulia || gotJuliaMagnitude != wantJuliaMagnitude {
+					t.Fatalf("interval %d, radius %v, z0 = (%v, %v): got (%d, %v), want (%d, %v)", interval, escapeRadius, realCoords[i], imagCoords[i], gotJulia, gotJuliaMagnitude, wantJulia, wantJuliaMagnitude)
+				}
+			}
+		}
+	}
+}
+
+// TestPeriodicityCheck checks that cycle detection stops a period-3 bulb
+// orbit early and leaves the counts of escaping points unchanged
+func TestPeriodicityCheck(t *testing.T) {
+	defer func(saved bool) { periodicityCheck = saved }(periodicityCheck)
+
+	// c = -0.12 + 0.75i is inside the period-3 bulb, which the cardioid and
+	// bulb test doesn't cover
+	if inCardioidOrBulb(-0.12, 0.75) {
+		t.Fatal("period-3 bulb point passed the cardioid and bulb test")
+	}
+	iterations, _, proven := periodicEscapeTime(0, 0, -0.12, 0.75, 1000000, 4)
+	if iterations != 1000000 || !proven {
+		t.Errorf("periodicEscapeTime(-0.12, 0.75) = (%d, proven %v), want (1000000, proven true)", iterations, proven)
+	}
+
+	realCoords, imagCoords := gridPoints(97, 61)
+	for i := range realCoords {
+		periodicityCheck = false
+		want, wantMagnitude := escapeTime(0, 0, realCoords[i], imagCoords[i], 500, 4)
+		if want == 500 {
+			continue
+		}
+
+		periodicityCheck = true
+		got, gotMagnitude := escapeTime(0, 0, realCoords[i], imagCoords[i], 500, 4)
+		if got != want || gotMagnitude != wantMagnitude {
+			t.Errorf("c = (%v, %v): got (%d, %v) with periodicity checking, want (%d, %v)", realCoords[i], imagCoords[i], got, gotMagnitude, want, wantMagnitude)
+		}
+	}
+}
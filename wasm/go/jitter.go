@@ -0,0 +1,84 @@
+//go:build js
+
+package main
+
+import (
+	"math/rand"
+	"syscall/js"
+)
+
+// Sample jitter
+//
+// With jitter enabled, the viewport renderers sample each pixel at a random
+// point inside it instead of at its own sample point. A single render is
+// noisier than an unjittered one, but averaging successive renders of the
+// same view in JS converges on the pixel's mean, smoothing edges over time
+// at the cost of one sample per pixel per render rather than supersampling's
+// several.
+//
+// The offsets come from sampleRand, a math/rand generator seeded via setSeed
+// that also draws estimateArea's sample points. They are drawn for the whole
+// viewport before iterating, in output order, so a given seed produces the
+// same sequence of renders whatever the worker count.
+
+// defaultSeed seeds sampleRand until setSeed is called
+const defaultSeed = 1
+
+// sampleJitter selects jittered sampling for the viewport renderers, changed
+// from JavaScript via setJitter, and sampleRand generates the random samples
+var (
+	sampleJitter = false
+	sampleRand   = rand.New(rand.NewSource(defaultSeed))
+)
+
+// setJitter enables or disables jittered sampling for the viewport renderers
+// (renderViewport, renderToMemory and renderTile)
+//
+// Neighbor guessing and symmetric rendering assume every pixel is sampled at
+// the same position within it, so they are not used while jitter is enabled.
+//
+// Parameters:
+//   - enabled: When true, each pixel is sampled at a random offset in
+//     [-0.5, 0.5) pixels from its sample point along each axis, the same
+//     square the supersampling patterns cover
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setJitter(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setJitter", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	sampleJitter = r.value(0).Truthy()
+	return true
+}
+
+// setSeed reseeds the random sample generator, so the jittered renders and
+// estimateArea calls that follow repeat those made after the same seed
+//
+// Parameters:
+//   - seed: Integer seed
+//
+// Returns:
+//   - true when the seed was applied, {error} for invalid arguments
+func setSeed(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setSeed", args, 1)
+	seed := r.integer(0, "seed")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	sampleRand.Seed(int64(seed))
+	return true
+}
+
+// jitterOffsets draws count sample offsets from sampleRand, each component
+// in [-0.5, 0.5)
+func jitterOffsets(count int) []sampleOffset {
+	offsets := make([]sampleOffset, count)
+	for i := range offsets {
+		offsets[i] = sampleOffset{dx: sampleRand.Float64() - 0.5, dy: sampleRand.Float64() - 0.5}
+	}
+	return offsets
+}
@@ -0,0 +1,50 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Strict coordinate lengths
+//
+// The batch functions that take separate real and imaginary coordinate
+// arrays process only the shorter length when the two differ. Mismatched
+// arrays are almost always a caller mistake, and silently computing fewer
+// points hides it, so setStrictLengths(true) makes those functions return
+// {error} instead. The lenient default is kept for existing callers.
+
+// strictLengths makes mismatched coordinate arrays an error instead of
+// truncating them, changed from JavaScript via setStrictLengths
+var strictLengths = false
+
+// setStrictLengths enables or disables strict length checking for the batch
+// functions that take separate real and imaginary coordinate arrays
+// (calculateMandelbrotSet, calculateMandelbrotSetTyped,
+// calculateMandelbrotSetBuffer, calculateJuliaSet, juliaKeyframe,
+// calculateBurningShipSet and calculateTricornSet)
+//
+// Parameters:
+//   - enabled: When true, arrays of different lengths return {error}; when
+//     false, only the shorter length is processed
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setStrictLengths(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setStrictLengths", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	strictLengths = r.value(0).Truthy()
+	return true
+}
+
+// matchingLengths records an error if strict length checking is on and the
+// real and imaginary coordinate arrays have different lengths
+func (r *argReader) matchingLengths(realCoords js.Value, realName string, imagCoords js.Value, imagName string) {
+	if r.failed() || !strictLengths {
+		return
+	}
+	r.check(realCoords.Length() == imagCoords.Length(), "%s and %s must have the same length, got %d and %d", realName, imagName, realCoords.Length(), imagCoords.Length())
+}
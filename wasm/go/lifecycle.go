@@ -0,0 +1,48 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// registeredFunc is a Go function exposed to JavaScript as a global
+type registeredFunc struct {
+	name string
+	fn   js.Func
+}
+
+// registeredFuncs holds every function set up by register, so shutdown can
+// release them
+var registeredFuncs []registeredFunc
+
+// shutdownRequested is closed by shutdown to let main return
+var shutdownRequested = make(chan struct{})
+
+// register exposes fn to JavaScript as the global name and tracks the
+// wrapping js.Func for release by shutdown
+func register(name string, fn func(this js.Value, args []js.Value) interface{}) {
+	wrapped := js.FuncOf(fn)
+	registeredFuncs = append(registeredFuncs, registeredFunc{name: name, fn: wrapped})
+	js.Global().Set(name, wrapped)
+}
+
+// shutdown tears the module down so the instance can be discarded, for
+// example when a page hot-reloads it
+//
+// Every registered global is removed and its js.Func released, then main is
+// unblocked and the Go program exits. Any reference to an export kept by
+// JavaScript stops working.
+//
+// Returns:
+//   - true once the functions have been released
+func shutdown(this js.Value, args []js.Value) interface{} {
+	for _, registered := range registeredFuncs {
+		js.Global().Delete(registered.name)
+		registered.fn.Release()
+	}
+	registeredFuncs = nil
+
+	close(shutdownRequested)
+	return true
+}
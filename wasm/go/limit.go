@@ -0,0 +1,51 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Iteration limit
+//
+// Every maxIterations argument is checked against maxIterationsLimit, so a
+// stray huge value from a bad zoom calculation returns {error} instead of
+// freezing the tab on an interior point that iterates for minutes. The
+// default of 10,000,000 iterations takes about a tenth of a second per
+// interior point, well beyond what any interactive view needs; deep zoom
+// tools that need more raise it with setMaxIterationsLimit.
+
+// defaultMaxIterationsLimit is the initial value of maxIterationsLimit
+const defaultMaxIterationsLimit = 10000000
+
+// maxIterationsLimit is the largest maxIterations any function accepts,
+// changed from JavaScript via setMaxIterationsLimit
+var maxIterationsLimit uint32 = defaultMaxIterationsLimit
+
+// setMaxIterationsLimit sets the largest maxIterations any function accepts
+//
+// Lowering the limit below the maxIterations stored by setDefaults lowers
+// the stored value to the limit, and unbounded calculatePoint calls stop at
+// the limit if it is below their safety cap.
+//
+// Parameters:
+//   - limit: The new limit, an integer from 1 to 4294967295. 10000000 is
+//     the default.
+//
+// Returns:
+//   - true when the limit was applied, {error} for invalid arguments
+func setMaxIterationsLimit(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setMaxIterationsLimit", args, 1)
+	limit := r.number(0, "limit")
+	r.check(limit >= 1 && limit <= math.MaxUint32 && limit == math.Trunc(limit), "limit must be an integer from 1 to %d, got %v", uint32(math.MaxUint32), limit)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	maxIterationsLimit = uint32(limit)
+	if defaultMaxIterations > maxIterationsLimit {
+		defaultMaxIterations = maxIterationsLimit
+	}
+	return true
+}
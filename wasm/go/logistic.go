@@ -0,0 +1,64 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// One-dimensional maps
+//
+// These iterate real maps rather than complex ones, for showing simpler
+// dynamical systems alongside the fractals. They share only the escape-time
+// idea with the complex functions: count the iterations until the orbit
+// leaves a bounded region.
+
+// calculateLogistic calculates the escape time of the logistic map
+// x = r*x*(1-x) from a starting value x0
+//
+// For 0 <= r <= 4 and x0 in [0, 1] the orbit stays in [0, 1] forever; larger
+// r or starting values outside [0, 1] let it run off to -infinity.
+//
+// Parameters:
+//   - r: Growth rate of the map
+//   - x0: Starting value
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeThreshold: Threshold beyond which |x| is considered escaped
+//
+// Returns:
+//   - The number of iterations before |x| exceeds escapeThreshold, or
+//     maxIterations if it doesn't. A NaN or infinite r or x0 returns 0.
+//     {error} for invalid arguments.
+func calculateLogistic(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateLogistic", args, 4)
+	growthRate := r.number(0, "r")
+	x0 := r.number(1, "x0")
+	maxIterations := r.maxIterations(2)
+	escapeThreshold := r.number(3, "escapeThreshold")
+	r.check(escapeThreshold > 0, "escapeThreshold must be greater than 0, got %v", escapeThreshold)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	return logisticEscapeTime(growthRate, x0, maxIterations, escapeThreshold)
+}
+
+// logisticEscapeTime iterates x = growthRate*x*(1-x) from x0 and returns the
+// iteration at which |x| first exceeds escapeThreshold, or maxIterations
+func logisticEscapeTime(growthRate, x0 float64, maxIterations uint32, escapeThreshold float64) uint32 {
+	if math.IsNaN(growthRate) || math.IsInf(growthRate, 0) || math.IsNaN(x0) || math.IsInf(x0, 0) {
+		return 0
+	}
+
+	x := x0
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		if math.Abs(x) > escapeThreshold {
+			return iteration
+		}
+		x = growthRate * x * (1 - x)
+	}
+
+	// Orbit did not escape within maxIterations
+	return maxIterations
+}
@@ -0,0 +1,97 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Lookup table coloring
+//
+// renderRGBAWithLUT colors escaped points from a caller-supplied table of
+// RGB entries instead of a built-in palette. An escaped point with n
+// iterations takes entry n mod the number of entries, so the table repeats
+// every len entries as the count rises; a table of maxIterations entries is
+// traversed exactly once. Interior points are black, as in renderRGBA.
+
+// fillRGBAWithLUT renders the viewport as RGBA bytes (4 per pixel, row-major)
+// into pixels, coloring escaped points from the lookup table colors
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillRGBAWithLUT(pixels []byte, colors []rgb, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	entries := uint32(len(colors))
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				iterations, _ := v.escapeTimeAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+				color := interiorColor
+				if iterations < maxIterations {
+					color = colors[iterations%entries]
+				}
+
+				i := (y*v.width + x) * 4
+				pixels[i] = color.r
+				pixels[i+1] = color.g
+				pixels[i+2] = color.b
+				pixels[i+3] = 255
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
+
+// renderRGBAWithLUT renders a viewport of the Mandelbrot set straight to RGBA
+// pixels, coloring each escaped point by its iteration count from a
+// caller-supplied lookup table
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - lutBuf: Uint8Array (or Uint8ClampedArray) of RGB triples, at least one
+//     entry; an escaped point with n iterations takes entry n mod the entry
+//     count
+//   - rgbaBuf: Uint8ClampedArray (or Uint8Array) of at least 4*width*height
+//     bytes, e.g. ImageData.data, receiving RGBA pixels in row-major order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffers are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderRGBAWithLUT(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderRGBAWithLUT", args, 9)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	lutBuf := r.byteArray(7, "lutBuf")
+	r.minLength(lutBuf, "lutBuf", 3)
+	if !r.failed() {
+		r.check(lutBuf.Length()%3 == 0, "lutBuf must hold RGB triples, got %d bytes", lutBuf.Length())
+	}
+	rgbaBuf := r.byteArray(8, "rgbaBuf")
+	r.minLength(rgbaBuf, "rgbaBuf", view.pixelCount()*4)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	colors := readPaletteColors(lutBuf)
+
+	beginRender()
+	pixels := make([]byte, view.pixelCount()*4)
+	completed := view.fillRGBAWithLUT(pixels, colors, maxIterations, escapeThreshold(escapeRadius))
+
+	js.CopyBytesToJS(rgbaBuf, pixels[:completed*4])
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -1,55 +1,185 @@
+//go:build js
+
 package main
 
 import (
+	"math"
 	"syscall/js"
 )
 
 // calculatePoint calculates the number of iterations for a point in the Mandelbrot set
 //
+// Accepts 4 to 9 arguments: (real, imag, maxIterations, escapeRadius), then
+// either smooth alone or z0Real, z0Imag and optionally smooth, bailoutShape
+// and checkInterval. Called with only (real, imag), the defaults from
+// setDefaults are used.
+//
 // Parameters:
 //   - real: Real component of the complex number c
 //   - imag: Imaginary component of the complex number c
-//   - maxIterations: Maximum number of iterations to perform
+//   - maxIterations: Maximum number of iterations to perform. 0 or negative
+//     iterates until escape, up to a safety cap of unboundedIterationCap.
 //   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - z0Real, z0Imag (optional): Starting value of z, defaulting to 0
+//   - smooth (optional): When true, return a continuous (fractional) iteration count
+//   - bailoutShape (optional): "circle" or "square", defaulting to the shape
+//     selected with setBailoutShape
+//   - checkInterval (optional): Iterations per escape test, at least 1,
+//     defaulting to the interval set with setCheckInterval
 //
 // Returns:
 //   - The number of iterations before escape, or maxIterations if the point doesn't escape
+//     (unboundedIterationCap when unbounded). With smooth set, the count is a float64 and non-escaping points return maxIterations.
+//     Points proven interior return the value set with setInteriorValue
+//     instead, if there is one.
+//     After setStructuredResults(true), an object {escaped, iterations, smooth}
+//     regardless of the smooth argument.
 func calculatePoint(this js.Value, args []js.Value) interface{} {
-	if len(args) != 4 {
-		return 0
+	r := readArgs("calculatePoint", args, 2, 4, 5, 6, 7, 8, 9)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations, escapeRadius := defaultMaxIterations, defaultEscapeRadius
+	if r.has(2) {
+		maxIterations = r.unboundedMaxIterations(2)
+		escapeRadius = r.escapeRadius(3)
+	}
+
+	z0Real, z0Imag := 0.0, 0.0
+	smooth := r.flag(4)
+	if len(args) >= 6 {
+		z0Real = r.number(4, "z0Real")
+		z0Imag = r.number(5, "z0Imag")
+		smooth = r.flag(6)
+	}
+	shape := r.bailoutShape(7)
+	interval := r.checkInterval(8)
+	if r.failed() {
+		return r.errorResult()
 	}
+	defer useBailoutShape(shape)()
+	defer useCheckInterval(interval)()
 
-	real := args[0].Float()
-	imag := args[1].Float()
-	maxIterations := uint32(args[2].Int())
-	escapeRadius := args[3].Float()
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
 
-	cReal := real
-	cImag := imag
+	// Points inside the main cardioid or period-2 bulb never escape. That
+	// only holds for orbits starting at zero.
+	if z0Real == 0 && z0Imag == 0 && escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(real, imag) {
+		if structuredResults {
+			return pointResult(maxIterations, 0, escapeRadiusSquared, 2)
+		}
+		if smooth {
+			return float64(reportedCount(maxIterations, true))
+		}
+		return reportedCount(maxIterations, true)
+	}
 
-	zReal := 0.0
-	zImag := 0.0
+	iterations, zMagnitudeSquared, proven := escapeTimeProven(z0Real, z0Imag, real, imag, maxIterations, escapeRadiusSquared)
 
-	escapeRadiusSquared := escapeRadius * escapeRadius
+	if structuredResults {
+		return pointResult(iterations, zMagnitudeSquared, escapeRadiusSquared, 2)
+	}
+	if !smooth {
+		return reportedCount(iterations, proven)
+	}
+	if iterations == maxIterations {
+		return float64(reportedCount(maxIterations, proven))
+	}
+	return smoothIterations(iterations, zMagnitudeSquared)
+}
 
-	for iteration := uint32(0); iteration < maxIterations; iteration++ {
-		// Calculate |z|^2 = z_real^2 + z_imag^2
-		zMagnitudeSquared := zReal*zReal + zImag*zImag
+// calculateNormalized calculates a point's Mandelbrot iteration count as a
+// fraction of maxIterations, for coloring without a palette lookup
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - iterations/maxIterations as a float64 in [0, 1]. Points that don't
+//     escape return exactly 1.
+func calculateNormalized(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateNormalized", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
 
-		// Check if point has escaped
-		if zMagnitudeSquared > escapeRadiusSquared {
-			return iteration
-		}
+	iterations := Iterate(real, imag, maxIterations, escapeThreshold(escapeRadius))
+	if iterations >= maxIterations {
+		return 1.0
+	}
+	return float64(iterations) / float64(maxIterations)
+}
+
+// isInSet reports whether a point belongs to the Mandelbrot set, for hit
+// testing
+//
+// Only membership is needed, not the escape iteration, so the interior
+// shortcuts are always applied: the cardioid and bulb test, and cycle
+// detection (with the epsilon configured by setPeriodicityCheck) whether or
+// not periodicity checking is enabled for the other functions. The escape
+// radius is fixed at 2.
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//
+// Returns:
+//   - true if the point does not escape within maxIterations; false for NaN
+//     or infinite coordinates
+func isInSet(this js.Value, args []js.Value) interface{} {
+	r := readArgs("isInSet", args, 3)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	if !isFinite(real, imag) {
+		return false
+	}
+	if inCardioidOrBulb(real, imag) {
+		return true
+	}
+
+	iterations, _ := escapeTimePeriodic(0, 0, real, imag, maxIterations, 4.0)
+	return iterations >= maxIterations
+}
+
+// smoothIterations converts an escape iteration into a continuous count using
+// the normalized iteration formula n + 1 - log2(log|z|).
+//
+// When |z| <= 1 at escape (only possible with an escape radius of 1 or less)
+// log|z| is not positive and the double logarithm is undefined, so the integer
+// iteration count is returned unchanged. So is the count of a NaN or infinite
+// point, whose magnitude escapeTime reports as infinite.
+func smoothIterations(iteration uint32, zMagnitudeSquared float64) float64 {
+	return smoothIterationsForPower(iteration, zMagnitudeSquared, 2)
+}
 
-		// Calculate z = z^2 + c
-		// (a + bi)^2 = a^2 - b^2 + 2abi
-		zRealTemp := zReal*zReal - zImag*zImag + cReal
-		zImag = 2.0*zReal*zImag + cImag
-		zReal = zRealTemp
+// smoothIterationsForPower is smoothIterations for z = z^power + c
+//
+// Near escape |z| grows roughly as |z|^power per iteration, so the fractional
+// part uses log base power: n + 1 - log_power(log|z|). Power 2 divides by the
+// exact constant math.Ln2 to stay identical to the quadratic formula.
+func smoothIterationsForPower(iteration uint32, zMagnitudeSquared float64, power int) float64 {
+	logMagnitude := math.Log(zMagnitudeSquared) / 2.0
+	if logMagnitude <= 0 || math.IsInf(logMagnitude, 1) {
+		return float64(iteration)
 	}
 
-	// Point did not escape within maxIterations
-	return maxIterations
+	logPower := math.Ln2
+	if power != 2 {
+		logPower = math.Log(float64(power))
+	}
+	return float64(iteration) + 1.0 - math.Log(logMagnitude)/logPower
 }
 
 // calculateMandelbrotSet calculates the Mandelbrot set for multiple points in a single batch call
@@ -59,76 +189,585 @@ func calculatePoint(this js.Value, args []js.Value) interface{} {
 //   - imagCoords: Array of imaginary components for all points
 //   - maxIterations: Maximum number of iterations to perform
 //   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - withRange (optional): When true, also report the range of escaped counts
+//   - bailoutShape (optional): "circle" or "square", defaulting to the shape
+//     selected with setBailoutShape
+//   - checkInterval (optional): Iterations per escape test, at least 1,
+//     defaulting to the interval set with setCheckInterval
+//
+// Called with only (realCoords, imagCoords), the defaults from setDefaults
+// are used.
+//
+// Arrays of different lengths are truncated to the shorter one, or rejected
+// with {error} while setStrictLengths is on.
 //
 // Returns:
-//   - Array of iteration counts, one for each input coordinate pair
+//   - Array of iteration counts, one for each input coordinate pair. With
+//     withRange set, an object {results, min, max} where min and max are the
+//     smallest and largest counts of points that escaped (interior points at
+//     maxIterations are excluded), or null when none did.
 func calculateMandelbrotSet(this js.Value, args []js.Value) interface{} {
-	if len(args) != 4 {
-		return js.ValueOf([]interface{}{})
+	r := readArgs("calculateMandelbrotSet", args, 2, 4, 5, 6, 7)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	maxIterations, escapeRadius := r.iterationSettings(2)
+	withRange := r.flag(4)
+	shape := r.bailoutShape(5)
+	interval := r.checkInterval(6)
+	if r.failed() {
+		return r.errorResult()
 	}
+	defer useBailoutShape(shape)()
+	defer useCheckInterval(interval)()
 
-	realCoords := args[0]
-	imagCoords := args[1]
-	maxIterations := uint32(args[2].Int())
-	escapeRadius := args[3].Float()
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
 
-	// Get array lengths
-	realLength := realCoords.Length()
-	imagLength := imagCoords.Length()
-	
-	// Use minimum length to handle mismatched arrays
-	length := realLength
-	if imagLength < length {
-		length = imagLength
+	beginRender()
+	if !withRange {
+		results, completed := computeMandelbrotBatch(readFloat64s(realCoords), readFloat64s(imagCoords), maxIterations, escapeRadiusSquared)
+		return iterationsArray(results, completed)
 	}
 
-	// Pre-allocate result array
-	results := make([]interface{}, length)
-	
-	escapeRadiusSquared := escapeRadius * escapeRadius
+	// The range is taken before proven interior points get the interior value
+	restore := markProvenInterior(maxIterations)
+	results, completed := computeMandelbrotBatch(readFloat64s(realCoords), readFloat64s(imagCoords), maxIterations, escapeRadiusSquared)
+	lowest, highest := escapedRange(results[:completed], maxIterations)
+	restore(results[:completed])
+	return map[string]interface{}{
+		"results": iterationsArray(results, completed),
+		"min":     lowest,
+		"max":     highest,
+	}
+}
+
+// calculateMandelbrotSetTyped calculates the Mandelbrot set for multiple points using
+// typed arrays, avoiding a JS boundary crossing per element
+//
+// Parameters:
+//   - realBuf: Float64Array of real components for all points
+//   - imagBuf: Float64Array of imaginary components for all points
+//   - resultBuf: Uint32Array that receives one iteration count per point
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - bailoutShape (optional): "circle" or "square", defaulting to the shape
+//     selected with setBailoutShape
+//   - checkInterval (optional): Iterations per escape test, at least 1,
+//     defaulting to the interval set with setCheckInterval
+//
+// Returns:
+//   - The number of results written, the minimum of the three buffer lengths.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func calculateMandelbrotSetTyped(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateMandelbrotSetTyped", args, 5, 6, 7)
+	realBuf := r.array(0, "realBuf")
+	imagBuf := r.array(1, "imagBuf")
+	r.matchingLengths(realBuf, "realBuf", imagBuf, "imagBuf")
+	resultBuf := r.typedArray(2, "resultBuf", "Uint32Array")
+	maxIterations := r.maxIterations(3)
+	escapeRadius := r.escapeRadius(4)
+	shape := r.bailoutShape(5)
+	interval := r.checkInterval(6)
+	if r.failed() {
+		return r.errorResult()
+	}
+	defer useBailoutShape(shape)()
+	defer useCheckInterval(interval)()
 
-	// Process each coordinate pair
-	for i := 0; i < length; i++ {
-		cReal := realCoords.Index(i).Float()
-		cImag := imagCoords.Index(i).Float()
+	realCoords := readFloat64s(realBuf)
+	imagCoords := readFloat64s(imagBuf)
 
-		zReal := 0.0
-		zImag := 0.0
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
 
-		iteration := uint32(0)
+	// Use minimum length to handle mismatched buffers
+	if resultLength := resultBuf.Length(); resultLength < len(realCoords) {
+		realCoords = realCoords[:resultLength]
+	}
 
-		for iter := uint32(0); iter < maxIterations; iter++ {
-			// Calculate |z|^2 = z_real^2 + z_imag^2
-			zMagnitudeSquared := zReal*zReal + zImag*zImag
+	beginRender()
+	results, completed := computeMandelbrotBatch(realCoords, imagCoords, maxIterations, escapeRadiusSquared)
 
-			// Check if point has escaped
-			if zMagnitudeSquared > escapeRadiusSquared {
-				iteration = iter
-				break
-			}
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
 
-			// Calculate z = z^2 + c
-			// (a + bi)^2 = a^2 - b^2 + 2abi
-			zRealTemp := zReal*zReal - zImag*zImag + cReal
-			zImag = 2.0*zReal*zImag + cImag
-			zReal = zRealTemp
+// calculateMandelbrotSetBuffer calculates the Mandelbrot set for multiple
+// points like calculateMandelbrotSet, returning the counts in a new
+// ArrayBuffer
+//
+// Unlike an array, the buffer can be passed to postMessage as a transferable,
+// moving it from a worker to the main thread without a structured clone.
+//
+// Parameters:
+//   - realCoords: Array or Float64Array of real components for all points
+//   - imagCoords: Array or Float64Array of imaginary components for all points
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - bailoutShape (optional): "circle" or "square", defaulting to the shape
+//     selected with setBailoutShape
+//   - checkInterval (optional): Iterations per escape test, at least 1,
+//     defaulting to the interval set with setCheckInterval
+//
+// Returns:
+//   - An ArrayBuffer of 4 bytes per point holding one little-endian uint32
+//     iteration count per point in input order, ready to view as a
+//     Uint32Array. If cancelRender stops the batch it holds only the points
+//     completed so far and has a cancelled property set to true. {error} for
+//     invalid arguments.
+func calculateMandelbrotSetBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateMandelbrotSetBuffer", args, 4, 5, 6)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	shape := r.bailoutShape(4)
+	interval := r.checkInterval(5)
+	if r.failed() {
+		return r.errorResult()
+	}
+	defer useBailoutShape(shape)()
+	defer useCheckInterval(interval)()
 
-			iteration = iter + 1
-		}
+	beginRender()
+	results, completed := computeMandelbrotBatch(readFloat64s(realCoords), readFloat64s(imagCoords), maxIterations, escapeThreshold(escapeRadius))
+
+	buffer := newUint32ArrayBuffer(results[:completed])
+	if completed < len(results) {
+		buffer.Set("cancelled", true)
+	}
+	return buffer
+}
+
+// calculateMandelbrotInterleaved calculates the Mandelbrot set for multiple
+// points like calculateMandelbrotSet, reading both components of every point
+// from a single array
+//
+// Parameters:
+//   - coords: Array or Float64Array of interleaved coordinates
+//     [real0, imag0, real1, imag1, ...], of even length. A Float64Array is
+//     copied in one CopyBytesToGo call.
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - bailoutShape (optional): "circle" or "square", defaulting to the shape
+//     selected with setBailoutShape
+//   - checkInterval (optional): Iterations per escape test, at least 1,
+//     defaulting to the interval set with setCheckInterval
+//
+// Returns:
+//   - Array of iteration counts, one for each coordinate pair, with a
+//     cancelled property like calculateMandelbrotSet when cancelRender stops
+//     the batch. {error} for invalid arguments.
+func calculateMandelbrotInterleaved(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateMandelbrotInterleaved", args, 3, 4, 5)
+	coords := r.array(0, "coords")
+	maxIterations := r.maxIterations(1)
+	escapeRadius := r.escapeRadius(2)
+	shape := r.bailoutShape(3)
+	interval := r.checkInterval(4)
+	if r.failed() {
+		return r.errorResult()
+	}
+	r.check(coords.Length()%2 == 0, "coords must hold real and imaginary pairs, got odd length %d", coords.Length())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	defer useBailoutShape(shape)()
+	defer useCheckInterval(interval)()
+
+	interleaved := readFloat64s(coords)
+	realCoords := make([]float64, len(interleaved)/2)
+	imagCoords := make([]float64, len(interleaved)/2)
+	for i := range realCoords {
+		realCoords[i] = interleaved[2*i]
+		imagCoords[i] = interleaved[2*i+1]
+	}
+
+	beginRender()
+	results, completed := computeMandelbrotBatch(realCoords, imagCoords, maxIterations, escapeThreshold(escapeRadius))
+	return iterationsArray(results, completed)
+}
+
+// calculateJuliaPoint calculates the number of iterations for a point in the Julia set
+// of the fixed parameter c
+//
+// Parameters:
+//   - zReal: Real component of the starting point z
+//   - zImag: Imaginary component of the starting point z
+//   - cReal: Real component of the fixed Julia parameter c
+//   - cImag: Imaginary component of the fixed Julia parameter c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The number of iterations before escape, or maxIterations if the point doesn't escape.
+//     After setStructuredResults(true), an object {escaped, iterations, smooth}.
+func calculateJuliaPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateJuliaPoint", args, 6)
+	zReal := r.number(0, "zReal")
+	zImag := r.number(1, "zImag")
+	cReal := r.number(2, "cReal")
+	cImag := r.number(3, "cImag")
+	maxIterations := r.maxIterations(4)
+	escapeRadius := r.escapeRadius(5)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	iterations, zMagnitudeSquared := escapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	if structuredResults {
+		return pointResult(iterations, zMagnitudeSquared, escapeRadiusSquared, 2)
+	}
+	return iterations
+}
+
+// calculateJuliaSet calculates the Julia set of the fixed parameter c for multiple
+// starting points in a single batch call
+//
+// Parameters:
+//   - realCoords: Array of real components of the starting points
+//   - imagCoords: Array of imaginary components of the starting points
+//   - cReal: Real component of the fixed Julia parameter c
+//   - cImag: Imaginary component of the fixed Julia parameter c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - Array of iteration counts, one for each input coordinate pair
+func calculateJuliaSet(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateJuliaSet", args, 6)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	cReal := r.number(2, "cReal")
+	cImag := r.number(3, "cImag")
+	maxIterations := r.maxIterations(4)
+	escapeRadius := r.escapeRadius(5)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	return calculateBatch(realCoords, imagCoords, func(zReal, zImag float64) uint32 {
+		return IterateJulia(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	})
+}
+
+// juliaKeyframe calculates one frame of a Julia set morphing animation: the
+// Julia set of c = (1-t)*cStart + t*cEnd for multiple starting points
+//
+// Stepping t from 0 to 1 over the frames moves c along the straight path from
+// cStart to cEnd, so each frame is a single call with only t changing. The
+// interpolation gives exactly cStart at t = 0 and cEnd at t = 1.
+//
+// Parameters:
+//   - realCoords: Array of real components of the starting points
+//   - imagCoords: Array of imaginary components of the starting points
+//   - cStartReal, cStartImag: The Julia parameter at t = 0
+//   - cEndReal, cEndImag: The Julia parameter at t = 1
+//   - t: Position along the path, in [0, 1]
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - Array of iteration counts, one for each input coordinate pair, as for
+//     calculateJuliaSet. {error} for invalid arguments.
+func juliaKeyframe(this js.Value, args []js.Value) interface{} {
+	r := readArgs("juliaKeyframe", args, 9)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	cStartReal := r.number(2, "cStartReal")
+	cStartImag := r.number(3, "cStartImag")
+	cEndReal := r.number(4, "cEndReal")
+	cEndImag := r.number(5, "cEndImag")
+	t := r.number(6, "t")
+	r.check(t >= 0 && t <= 1, "t must be between 0 and 1, got %v", t)
+	maxIterations := r.maxIterations(7)
+	escapeRadius := r.escapeRadius(8)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	cReal := (1-t)*cStartReal + t*cEndReal
+	cImag := (1-t)*cStartImag + t*cEndImag
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	return calculateBatch(realCoords, imagCoords, func(zReal, zImag float64) uint32 {
+		return IterateJulia(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	})
+}
+
+// calculateBatch applies pointFn to every (real, imag) pair read from two JS arrays
+//
+// Mismatched arrays are handled by processing only the shorter length.
+// Returns a JS array of iteration counts in input order. If cancelRender stops
+// the batch, the array holds only the completed leading results and has a
+// cancelled property set to true.
+func calculateBatch(realCoords, imagCoords js.Value, pointFn func(real, imag float64) uint32) js.Value {
+	beginRender()
+	results, completed := computeBatch(readFloat64s(realCoords), readFloat64s(imagCoords), pointFn)
+	return iterationsArray(results, completed)
+}
+
+// iterationsArray converts the first completed results of a batch to a JS
+// array, with a cancelled property set to true if the batch stopped early
+func iterationsArray(results []uint32, completed int) js.Value {
+	// Convert to a JS array of iteration counts
+	values := make([]interface{}, completed)
+	for i := range values {
+		values[i] = results[i]
+	}
+
+	array := js.ValueOf(values)
+	if completed < len(results) {
+		array.Set("cancelled", true)
+	}
+	return array
+}
 
-		results[i] = iteration
+// escapedRange returns the smallest and largest iteration counts among the
+// results below maxIterations, ignoring interior points, or nil for both when
+// no point escaped. Proven interior points must still be marked as by
+// markProvenInterior.
+func escapedRange(results []uint32, maxIterations uint32) (interface{}, interface{}) {
+	found := false
+	var lowest, highest uint32
+	for _, iterations := range results {
+		if iterations >= maxIterations {
+			continue
+		}
+		if !found || iterations < lowest {
+			lowest = iterations
+		}
+		if !found || iterations > highest {
+			highest = iterations
+		}
+		found = true
 	}
 
-	return js.ValueOf(results)
+	if !found {
+		return nil, nil
+	}
+	return lowest, highest
 }
 
 func main() {
 	// Register the calculatePoint function to be callable from JavaScript
-	js.Global().Set("calculatePoint", js.FuncOf(calculatePoint))
+	register("calculatePoint", calculatePoint)
+	register("calculateNormalized", calculateNormalized)
+	register("isInSet", isInSet)
 	
 	// Register the batch calculation function
-	js.Global().Set("calculateMandelbrotSet", js.FuncOf(calculateMandelbrotSet))
+	register("calculateMandelbrotSet", calculateMandelbrotSet)
+	register("calculateMandelbrotSetTyped", calculateMandelbrotSetTyped)
+	register("calculateMandelbrotSetBuffer", calculateMandelbrotSetBuffer)
+	register("calculateMandelbrotInterleaved", calculateMandelbrotInterleaved)
+
+	// Register the Julia set functions
+	register("calculateJuliaPoint", calculateJuliaPoint)
+	register("calculateJuliaSet", calculateJuliaSet)
+	register("juliaKeyframe", juliaKeyframe)
+
+	// Register the Mandelbrot to Julia blend renderer
+	register("renderBlend", renderBlend)
+
+	// Register the viewport renderer
+	register("renderViewport", renderViewport)
+	register("generateCoordinates", generateCoordinates)
+	register("computeGrid", computeGrid)
+
+	// Register the streaming row renderer
+	register("renderRows", renderRows)
+
+	// Register the scanline reference
+	register("computeScanline", computeScanline)
+
+	// Register the progressive pass renderer
+	register("renderViewportPass", renderViewportPass)
+
+	// Register the stateful progressive renderer
+	register("createRenderer", createRenderer)
+	register("renderStep", renderStep)
+	register("getBuffer", getBuffer)
+	register("destroyRenderer", destroyRenderer)
+
+	// Register the iteration-budgeted renderer
+	register("renderViewportBudget", renderViewportBudget)
+
+	// Register the resumable renderer
+	register("renderViewportState", renderViewportState)
+	register("continueRender", continueRender)
+
+	// Register the perturbation renderer
+	register("renderPerturbation", renderPerturbation)
+
+	// Register the series approximation functions
+	register("setSeriesApproximation", setSeriesApproximation)
+	register("calculateSeriesSkip", calculateSeriesSkip)
+
+	// Register the Mariani-Silver subdivision renderer
+	register("renderMarianiSilver", renderMarianiSilver)
+
+	// Register the map tile renderer
+	register("renderTile", renderTile)
+
+	// Register the exponential map renderer
+	register("renderExponentialMap", renderExponentialMap)
+
+	// Register the RGBA renderer
+	register("renderRGBA", renderRGBA)
+	register("setPalette", setPalette)
+	register("setColoringMode", setColoringMode)
+	register("computeHistogram", computeHistogram)
+
+	// Register the log-scaled smooth RGBA renderer
+	register("renderRGBASmooth", renderRGBASmooth)
+
+	// Register the lookup table RGBA renderer
+	register("renderRGBAWithLUT", renderRGBAWithLUT)
+
+	// Register the float32 smooth renderer
+	register("renderSmoothFloat32", renderSmoothFloat32)
+	register("renderFloatTexture", renderFloatTexture)
+
+	// Register the slope shading renderer
+	register("renderNormals", renderNormals)
+
+	// Register the linear memory renderer
+	register("getMemoryBuffer", getMemoryBuffer)
+	register("renderToMemory", renderToMemory)
+	register("allocBuffer", allocBuffer)
+	register("freeBuffer", freeBuffer)
+
+	// Register the frame diffing function
+	register("diffRegion", diffRegion)
+
+	// Register the buffer hash
+	register("hashBuffer", hashBuffer)
+
+	// Register the delta encoding functions
+	register("encodeDeltas", encodeDeltas)
+	register("decodeDeltas", decodeDeltas)
+
+	// Register the multibrot function
+	register("calculateMultibrotPoint", calculateMultibrotPoint)
+
+	// Register the Burning Ship functions
+	register("calculateBurningShipPoint", calculateBurningShipPoint)
+	register("calculateBurningShipSet", calculateBurningShipSet)
+
+	// Register the Tricorn functions
+	register("calculateTricornPoint", calculateTricornPoint)
+	register("calculateTricornSet", calculateTricornSet)
+
+	// Register the root-finding fractal functions
+	register("calculateNewtonPoint", calculateNewtonPoint)
+	register("calculateHalleyPoint", calculateHalleyPoint)
+
+	// Register the one-dimensional map functions
+	register("calculateLogistic", calculateLogistic)
+
+	// Register the orbit detail functions
+	register("calculatePointWithMagnitude", calculatePointWithMagnitude)
+	register("calculatePointWithPhase", calculatePointWithPhase)
+	register("calculatePotential", calculatePotential)
+	register("calculateOrbitTrapPoint", calculateOrbitTrapPoint)
+	register("calculateOrbitTrapLine", calculateOrbitTrapLine)
+	register("calculateOrbitTrapCross", calculateOrbitTrapCross)
+	register("calculateOrbit", calculateOrbit)
+	register("calculateLinearEscapeTime", calculateLinearEscapeTime)
+	register("calculateLemniscateLevel", calculateLemniscateLevel)
+
+	// Register the minibrot nucleus search
+	register("findNearbyPeriodicPoint", findNearbyPeriodicPoint)
+
+	// Register the external ray tracer
+	register("traceExternalRay", traceExternalRay)
+
+	// Register the stripe average coloring function
+	register("calculateStripePoint", calculateStripePoint)
+
+	// Register the distance estimator
+	register("calculateDistanceEstimate", calculateDistanceEstimate)
+
+	// Register the region statistics function
+	register("regionStats", regionStats)
+
+	// Register the area estimate
+	register("estimateArea", estimateArea)
+
+	// Register the Buddhabrot accumulation function
+	register("accumulateBuddhabrot", accumulateBuddhabrot)
+
+	// Register the parallelism setting
+	register("setWorkerCount", setWorkerCount)
+	register("getConcurrency", getConcurrency)
+
+	// Register render cancellation
+	register("cancelRender", cancelRender)
+
+	// Register the throughput benchmark
+	register("benchmarkIterations", benchmarkIterations)
+
+	// Register the periodicity checking toggle
+	register("setPeriodicityCheck", setPeriodicityCheck)
+
+	// Register the double-double precision toggle
+	register("setHighPrecision", setHighPrecision)
+
+	// Register the fixed-point arithmetic toggle
+	register("setFixedPoint", setFixedPoint)
+
+	// Register the precision check
+	register("checkPrecision", checkPrecision)
+
+	// Register the neighbor guessing toggle
+	register("setNeighborGuessing", setNeighborGuessing)
+
+	// Register the sample jitter settings
+	register("setJitter", setJitter)
+	register("setSeed", setSeed)
+
+	// Register the bailout shape selector
+	register("setBailoutShape", setBailoutShape)
+
+	// Register the inclusive escape toggle
+	register("setInclusiveEscape", setInclusiveEscape)
+
+	// Register the scale bounds setting
+	register("setBounds", setBounds)
+
+	// Register the escape check interval setting
+	register("setCheckInterval", setCheckInterval)
+
+	// Register the interior value setting
+	register("setInteriorValue", setInteriorValue)
+
+	// Register the strict coordinate length toggle
+	register("setStrictLengths", setStrictLengths)
+
+	// Register the single-point result format toggle
+	register("setStructuredResults", setStructuredResults)
+
+	// Register the default iteration settings
+	register("setDefaults", setDefaults)
+	register("recommendedIterations", recommendedIterations)
+	register("setMaxIterationsLimit", setMaxIterationsLimit)
+
+	// Register the capability report
+	register("getCapabilities", getCapabilities)
+
+	// Register teardown
+	register("shutdown", shutdown)
 
-	// Keep the program running
-	select {}
+	// Keep the program running until shutdown is called
+	<-shutdownRequested
 }
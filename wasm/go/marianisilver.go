@@ -0,0 +1,152 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// marianiSilverMinTile is the side length at or below which a rectangle is
+// computed pixel by pixel instead of being subdivided further
+const marianiSilverMinTile = 4
+
+// marianiSilverTile fills results for the rectangle [x0, x1) x [y0, y1) using
+// Mariani-Silver subdivision
+//
+// The border of the rectangle is iterated first. If every border pixel has
+// the same count, the interior is filled with it without iterating; otherwise
+// the rectangle is split into quadrants that are handled the same way. done
+// marks pixels that already hold their value, so borders shared between a
+// rectangle and its quadrants are only iterated once.
+//
+// Returns false if the render was cancelled before the rectangle was filled.
+func (v viewport) marianiSilverTile(results []uint32, done []bool, x0, y0, x1, y1 int, maxIterations uint32, escapeRadiusSquared float64) bool {
+	if isRenderCancelled() {
+		return false
+	}
+
+	pixel := func(x, y int) uint32 {
+		index := y*v.width + x
+		if !done[index] {
+			results[index], _ = v.escapeTimeAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+			done[index] = true
+		}
+		return results[index]
+	}
+
+	if x1-x0 <= marianiSilverMinTile || y1-y0 <= marianiSilverMinTile {
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				pixel(x, y)
+			}
+		}
+		return true
+	}
+
+	first := pixel(x0, y0)
+	uniform := true
+	for x := x0; x < x1; x++ {
+		uniform = pixel(x, y0) == first && uniform
+		uniform = pixel(x, y1-1) == first && uniform
+	}
+	for y := y0 + 1; y < y1-1; y++ {
+		uniform = pixel(x0, y) == first && uniform
+		uniform = pixel(x1-1, y) == first && uniform
+	}
+
+	if uniform {
+		for y := y0 + 1; y < y1-1; y++ {
+			for x := x0 + 1; x < x1-1; x++ {
+				index := y*v.width + x
+				results[index] = first
+				done[index] = true
+			}
+		}
+		return true
+	}
+
+	midX := (x0 + x1) / 2
+	midY := (y0 + y1) / 2
+	return v.marianiSilverTile(results, done, x0, y0, midX, midY, maxIterations, escapeRadiusSquared) &&
+		v.marianiSilverTile(results, done, midX, y0, x1, midY, maxIterations, escapeRadiusSquared) &&
+		v.marianiSilverTile(results, done, x0, midY, midX, y1, maxIterations, escapeRadiusSquared) &&
+		v.marianiSilverTile(results, done, midX, midY, x1, y1, maxIterations, escapeRadiusSquared)
+}
+
+// fillMarianiSilver is fillEscapeTimes using Mariani-Silver subdivision
+//
+// Rows are split into one band per worker and each band is subdivided on its
+// own, so workers never touch the same pixels.
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillMarianiSilver(results []uint32, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	done := make([]bool, v.pixelCount())
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		if v.marianiSilverTile(results, done, 0, startRow, v.width, endRow, maxIterations, escapeRadiusSquared) {
+			return endRow - startRow
+		}
+
+		// Subdivision doesn't fill row by row, so count the leading rows that
+		// happen to be complete
+		for y := startRow; y < endRow; y++ {
+			for x := 0; x < v.width; x++ {
+				if !done[y*v.width+x] {
+					return y - startRow
+				}
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
+
+// renderMarianiSilver renders a viewport like renderViewport, skipping the
+// iteration of regions enclosed by a border of uniform iteration counts
+//
+// Large interior areas and bands of equal escape count are filled from their
+// borders, which makes zoomed-out and deep-interior views much faster. The
+// shortcut relies on the connectedness of the Mandelbrot set and its level
+// sets; detail smaller than the enclosing rectangle that doesn't reach its
+// border, such as a minibrot inside a single escape band, can be missed.
+//
+// Parameters:
+//   - width: Viewport width in pixels
+//   - height: Viewport height in pixels
+//   - centerReal: Real component at the center of the viewport
+//   - centerImag: Imaginary component at the center of the viewport
+//   - scale: Complex-plane units per pixel
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     If cancelRender stops the render, an object {written, cancelled: true}
+//     where written counts the leading pixels that were filled.
+func renderMarianiSilver(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderMarianiSilver", args, 8)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	resultBuf := r.typedArray(7, "resultBuf", "Uint32Array")
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results := make([]uint32, view.pixelCount())
+	completed := view.fillMarianiSilver(results, maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,282 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+	"unsafe"
+)
+
+// Rendering straight into WebAssembly linear memory
+//
+// The module owns byte slices that JS addresses by their offsets in linear
+// memory (the slices' addresses, since Go pointers on wasm are linear memory
+// offsets): the single region of getMemoryBuffer, and any number of regions
+// reserved with allocBuffer. JS views one with
+//
+//	new Uint32Array(instance.exports.mem.buffer, offset, width * height)
+//
+// and must recreate the view after any call that can grow memory, because
+// growing detaches the previous ArrayBuffer.
+
+// maxRegionBytes is the largest region getMemoryBuffer and allocBuffer
+// reserve: wasm32 linear memory spans 4 GiB, and regions are rounded up to
+// whole uint64s. Larger requests could never be met and would stop the
+// runtime for every export.
+const maxRegionBytes = 1<<32 - 8
+
+// regionLength returns a byteLength argument from 1 to maxRegionBytes
+func (r *argReader) regionLength(index int) int {
+	value := r.number(index, "byteLength")
+	r.check(value >= 1 && value <= maxRegionBytes, "byteLength must be from 1 to %d, got %v", maxRegionBytes, value)
+	if r.failed() {
+		return 0
+	}
+	return int(value)
+}
+
+// memoryBuffer is the Go-owned region of getMemoryBuffer. It is kept in a
+// package variable so the garbage collector never frees or moves it.
+var memoryBuffer []byte
+
+// getMemoryBuffer reserves a region of linear memory for renderToMemory
+//
+// Parameters:
+//   - byteLength: Minimum size of the region in bytes, from 1 to 4294967288
+//
+// Returns:
+//   - The byte offset of the region. The previous region is reused when it is
+//     already large enough; otherwise it is replaced and earlier offsets become invalid.
+//     {error} for invalid arguments.
+func getMemoryBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("getMemoryBuffer", args, 1)
+	byteLength := r.regionLength(0)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	if len(memoryBuffer) < byteLength {
+		// Round up to whole uint64s so the allocation is 8-byte aligned
+		memoryBuffer = make([]byte, (byteLength+7)/8*8)
+	}
+
+	return memoryOffset(memoryBuffer)
+}
+
+// memoryOffset returns the linear memory offset of the first byte of buf
+func memoryOffset(buf []byte) int {
+	return int(uintptr(unsafe.Pointer(&buf[0])))
+}
+
+// allocatedBuffers holds the regions reserved with allocBuffer by their
+// offsets. Like memoryBuffer they are referenced from a package variable, so
+// the garbage collector never frees or moves them until freeBuffer drops them.
+var allocatedBuffers = map[int][]byte{}
+
+// allocBuffer reserves a new region of linear memory that stays valid until
+// freeBuffer releases it
+//
+// Unlike getMemoryBuffer, every call returns a separate region, so JS can
+// keep several buffers, such as one per frame in flight, without one
+// request replacing another.
+//
+// Parameters:
+//   - byteLength: Size of the region in bytes, from 1 to 4294967288
+//
+// Returns:
+//   - The 8-byte aligned byte offset of the region, or {error} for invalid
+//     arguments
+func allocBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("allocBuffer", args, 1)
+	byteLength := r.regionLength(0)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// Round up to whole uint64s so the allocation is 8-byte aligned
+	buf := make([]byte, (byteLength+7)/8*8)
+	offset := memoryOffset(buf)
+	allocatedBuffers[offset] = buf
+	return offset
+}
+
+// freeBuffer releases a region reserved with allocBuffer; its offset must not
+// be used afterwards
+//
+// Parameters:
+//   - offset: Offset returned by allocBuffer
+//
+// Returns:
+//   - true when the region was released, {error} if offset is not the start
+//     of a region that is still allocated
+func freeBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("freeBuffer", args, 1)
+	offset := r.integer(0, "offset")
+	_, ok := allocatedBuffers[offset]
+	r.check(ok, "no buffer allocated by allocBuffer at offset %d", offset)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	delete(allocatedBuffers, offset)
+	return true
+}
+
+// memoryRegion returns the byteLength bytes from offset, or false when that
+// range is not entirely inside memoryBuffer or a single region reserved with
+// allocBuffer
+func memoryRegion(offset, byteLength int) ([]byte, bool) {
+	if byteLength < 0 {
+		return nil, false
+	}
+
+	if region, ok := regionOf(memoryBuffer, offset, byteLength); ok {
+		return region, true
+	}
+	for _, buf := range allocatedBuffers {
+		if region, ok := regionOf(buf, offset, byteLength); ok {
+			return region, true
+		}
+	}
+	return nil, false
+}
+
+// regionOf returns the byteLength bytes of buf from linear memory offset, or
+// false when that range is not entirely inside buf
+func regionOf(buf []byte, offset, byteLength int) ([]byte, bool) {
+	if len(buf) == 0 {
+		return nil, false
+	}
+
+	start := offset - memoryOffset(buf)
+	if start < 0 || start+byteLength > len(buf) {
+		return nil, false
+	}
+
+	return buf[start : start+byteLength], true
+}
+
+// uint32Region returns count uint32 values of reserved memory starting at offset,
+// or false when the range is out of bounds or offset is not 4-byte aligned
+func uint32Region(offset, count int) ([]uint32, bool) {
+	if offset%4 != 0 {
+		return nil, false
+	}
+
+	region, ok := memoryRegion(offset, count*4)
+	if !ok {
+		return nil, false
+	}
+	if count == 0 {
+		return []uint32{}, true
+	}
+
+	return unsafe.Slice((*uint32)(unsafe.Pointer(&region[0])), count), true
+}
+
+// packInteriorMask sets bit i%8 of mask[i/8] for every result i that reached
+// maxIterations and clears the bits of all other pixels. Proven interior
+// points must still be marked as by markProvenInterior.
+func packInteriorMask(mask []byte, results []uint32, maxIterations uint32) {
+	for i := range mask {
+		mask[i] = 0
+	}
+	for i, iterations := range results {
+		if iterations >= maxIterations {
+			mask[i/8] |= 1 << (i % 8)
+		}
+	}
+}
+
+// renderToMemory calculates the Mandelbrot set for every pixel of a viewport and
+// writes the iteration counts directly into linear memory
+//
+// Layout: width*height little-endian unsigned values of bytesPerPixel (B)
+// bytes in row-major order, so pixel (x, y) is at byte offset + B*(y*width + x),
+// or in column-major order at byte offset + B*(x*height + y). Row 0 is the
+// top edge. Full uint32 values are computed in place, with no intermediate
+// copy.
+//
+// Parameters:
+//   - offset: Byte offset inside the region returned by getMemoryBuffer or
+//     a region reserved with allocBuffer
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - columnMajor (optional): When true, write the values in column-major order
+//   - bytesPerPixel (optional): Size of each value, 1, 2 or 4 (the default).
+//     Counts too large for 1 or 2 bytes are clamped to 255 or 65535. Narrow
+//     values are computed first and then packed into the region, and offset
+//     must be aligned to bytesPerPixel.
+//   - maskOffset (optional): Byte offset inside a reserved region of a
+//     ceil(width*height/8) byte interior mask, which must not overlap the
+//     iteration values. The pixel stored at index i of the values has bit
+//     i%8 (least significant first) of mask byte i/8 set when it reached
+//     maxIterations. Unused bits of the last byte are 0.
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments are invalid,
+//     the offset is misaligned or the output would not fit inside the
+//     reserved region. A cancelled render returns {written, cancelled: true}
+//     like renderViewport.
+func renderToMemory(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderToMemory", args, 8, 9, 10, 11)
+	offset := r.integer(0, "offset")
+	view := r.viewport(1)
+	maxIterations := r.maxIterations(6)
+	escapeRadius := r.escapeRadius(7)
+	columnMajor := r.flag(8)
+	bytesPerPixel := r.bytesPerPixel(9)
+	withMask := r.has(10)
+	maskOffset := 0
+	if withMask {
+		maskOffset = r.integer(10, "maskOffset")
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	valuesLength := view.pixelCount() * bytesPerPixel
+	region, ok := memoryRegion(offset, valuesLength)
+	r.check(ok && offset%bytesPerPixel == 0, "%d pixels at offset %d don't fit in the reserved region or the offset is not %d-byte aligned", view.pixelCount(), offset, bytesPerPixel)
+
+	var mask []byte
+	if withMask {
+		maskLength := (view.pixelCount() + 7) / 8
+		mask, ok = memoryRegion(maskOffset, maskLength)
+		r.check(ok, "a %d byte mask at offset %d doesn't fit in the reserved region", maskLength, maskOffset)
+		r.check(maskOffset+maskLength <= offset || maskOffset >= offset+valuesLength, "the mask at offset %d overlaps the iteration values", maskOffset)
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// The mask is taken before proven interior points get the interior value
+	restore := func([]uint32) {}
+	if withMask {
+		restore = markProvenInterior(maxIterations)
+	}
+
+	beginRender()
+	var results []uint32
+	var completed int
+	if bytesPerPixel == 4 {
+		results, _ = uint32Region(offset, view.pixelCount())
+		completed = view.fillEscapeTimes(results, columnMajor, maxIterations, escapeThreshold(escapeRadius))
+	} else {
+		results, completed = view.escapeTimes(columnMajor, maxIterations, escapeThreshold(escapeRadius))
+	}
+
+	if withMask {
+		packInteriorMask(mask, results[:completed], maxIterations)
+	}
+	restore(results[:completed])
+	if bytesPerPixel != 4 {
+		packIterations(region, results[:completed], bytesPerPixel)
+	}
+
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,78 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// maxMultibrotPower bounds the exponent of calculateMultibrotPoint. Each
+// iteration costs power - 1 complex multiplications, so the limit on
+// maxIterations alone would not keep a huge power from hanging the tab.
+const maxMultibrotPower = 64
+
+// calculateMultibrotPoint calculates the number of iterations for a point in the
+// multibrot set z = z^power + c
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - power: Integer exponent, from 2 to 64
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped, or
+//     0 for the bailout radius of the power, see multibrotEscapeRadius
+//   - smooth (optional): When true, return a continuous (fractional) iteration
+//     count using log base power
+//
+// Returns:
+//   - The number of iterations before escape, or maxIterations if the point doesn't escape.
+//     With smooth set, the count is a float64 and non-escaping points return maxIterations.
+//     Returns {error} for a power outside [2, 64]. After setStructuredResults(true), an
+//     object {escaped, iterations, smooth}.
+func calculateMultibrotPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateMultibrotPoint", args, 5, 6)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	powerValue := r.number(2, "power")
+	maxIterations := r.maxIterations(3)
+	escapeRadius := r.number(4, "escapeRadius")
+	if escapeRadius != 0 {
+		escapeRadius = r.escapeRadius(4)
+	}
+	smooth := r.flag(5)
+	r.check(powerValue >= 2 && powerValue <= maxMultibrotPower, "power must be from 2 to %d, got %v", maxMultibrotPower, powerValue)
+	if r.failed() {
+		return r.errorResult()
+	}
+	power := int(powerValue)
+	if escapeRadius == 0 {
+		escapeRadius = multibrotEscapeRadius(real, imag, power)
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	iterations, zMagnitudeSquared := multibrotEscapeTime(0, 0, real, imag, power, maxIterations, escapeRadiusSquared)
+	if structuredResults {
+		return pointResult(iterations, zMagnitudeSquared, escapeRadiusSquared, power)
+	}
+	if !smooth {
+		return iterations
+	}
+	if iterations == maxIterations {
+		return float64(maxIterations)
+	}
+	return smoothIterationsForPower(iterations, zMagnitudeSquared, power)
+}
+
+// multibrotEscapeRadius returns the smallest escape radius that is exact for
+// z = z^power + c: max(|c|, 2^(1/(power-1)))
+//
+// Once |z| exceeds both |c| and 2^(1/(power-1)), |z|^power - |c| > |z|, so
+// |z| grows on every iteration and the point is certain to escape. For power
+// 2 this is the familiar radius 2 whenever |c| <= 2. Higher powers get a
+// smaller radius, approaching 1, that follows the tighter boundary of their
+// sets.
+func multibrotEscapeRadius(cReal, cImag float64, power int) float64 {
+	return math.Max(math.Hypot(cReal, cImag), math.Pow(2, 1/float64(power-1)))
+}
@@ -0,0 +1,21 @@
+//go:build !js
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Native builds
+//
+// Everything that talks to JavaScript is built only for GOOS=js. Outside it
+// the package holds just the iteration core (iterate.go and the settings it
+// follows), so go test runs its tests natively and the core compiles into
+// native programs. There is nothing to register, so main only explains how
+// to build the module.
+
+func main() {
+	fmt.Fprintln(os.Stderr, "mandelbrot: the WebAssembly module is built with GOOS=js GOARCH=wasm")
+	os.Exit(1)
+}
@@ -0,0 +1,162 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// cubeRootsOfUnity are the roots of z^3 - 1, indexed as reported to
+// JavaScript: 1, then e^(2πi/3), then e^(-2πi/3)
+var cubeRootsOfUnity = [3][2]float64{
+	{1, 0},
+	{-0.5, math.Sqrt(3) / 2},
+	{-0.5, -math.Sqrt(3) / 2},
+}
+
+// nearestCubeRoot returns the index of the cube root of unity within
+// tolerance of z, or -1 if z is not that close to any of them
+func nearestCubeRoot(zReal, zImag, tolerance float64) int {
+	toleranceSquared := tolerance * tolerance
+	for i, root := range cubeRootsOfUnity {
+		dReal := zReal - root[0]
+		dImag := zImag - root[1]
+		if dReal*dReal+dImag*dImag <= toleranceSquared {
+			return i
+		}
+	}
+	return -1
+}
+
+// newtonConvergence iterates Newton's method for z^3 - 1 starting from
+// z = zReal + zImag*i
+//
+// Returns the iteration at which z came within tolerance of a root and that
+// root's index, or maxIterations and -1 if it never did. Orbits that reach
+// z = 0, where the derivative vanishes, stop and are reported as not
+// converging.
+func newtonConvergence(zReal, zImag float64, maxIterations uint32, tolerance float64) (uint32, int) {
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		if root := nearestCubeRoot(zReal, zImag, tolerance); root >= 0 {
+			return iteration, root
+		}
+
+		// z - (z^3 - 1)/(3z^2) simplifies to 2z/3 + 1/(3z^2), and
+		// 1/(3z^2) = conj(z^2) / (3|z^2|^2)
+		zSquaredReal := zReal*zReal - zImag*zImag
+		zSquaredImag := 2.0 * zReal * zImag
+		denominator := 3.0 * (zSquaredReal*zSquaredReal + zSquaredImag*zSquaredImag)
+		if denominator == 0 {
+			break
+		}
+
+		zReal = 2.0*zReal/3.0 + zSquaredReal/denominator
+		zImag = 2.0*zImag/3.0 - zSquaredImag/denominator
+	}
+
+	// z did not converge within maxIterations
+	return maxIterations, -1
+}
+
+// calculateNewtonPoint calculates which cube root of unity Newton's method
+// for z^3 - 1 converges to from a starting point, and how quickly
+//
+// Parameters:
+//   - real: Real component of the starting point z
+//   - imag: Imaginary component of the starting point z
+//   - maxIterations: Maximum number of iterations to perform
+//   - tolerance: Distance from a root at which z counts as converged
+//
+// Returns:
+//   - An object {iterations, root}: the iteration at which z came within
+//     tolerance of a root and the root's index (0 for 1, 1 for e^(2πi/3),
+//     2 for e^(-2πi/3)). Points that don't converge within maxIterations
+//     return maxIterations and root -1.
+func calculateNewtonPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateNewtonPoint", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	tolerance := r.number(3, "tolerance")
+	r.check(tolerance > 0, "tolerance must be greater than 0, got %v", tolerance)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations, root := newtonConvergence(real, imag, maxIterations, tolerance)
+
+	return map[string]interface{}{
+		"iterations": iterations,
+		"root":       root,
+	}
+}
+
+// halleyConvergence iterates Halley's method for z^3 - 1 starting from
+// z = zReal + zImag*i
+//
+// Returns the same values as newtonConvergence. Halley's step cancels the
+// error to third order where Newton's cancels it to second, so orbits
+// converge in fewer steps and the basin boundaries are shaped differently.
+// Orbits that reach a zero of 2z^3 + 1, where the step is undefined, stop and
+// are reported as not converging; z = 0 is a fixed point and never converges.
+func halleyConvergence(zReal, zImag float64, maxIterations uint32, tolerance float64) (uint32, int) {
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		if root := nearestCubeRoot(zReal, zImag, tolerance); root >= 0 {
+			return iteration, root
+		}
+
+		// With f = z^3 - 1, z - 2ff'/(2f'^2 - ff'') simplifies to
+		// z(z^3 + 2)/(2z^3 + 1)
+		zSquaredReal := zReal*zReal - zImag*zImag
+		zSquaredImag := 2.0 * zReal * zImag
+		zCubedReal := zSquaredReal*zReal - zSquaredImag*zImag
+		zCubedImag := zSquaredReal*zImag + zSquaredImag*zReal
+
+		numeratorReal := zReal*(zCubedReal+2.0) - zImag*zCubedImag
+		numeratorImag := zReal*zCubedImag + zImag*(zCubedReal+2.0)
+		denominatorReal := 2.0*zCubedReal + 1.0
+		denominatorImag := 2.0 * zCubedImag
+		denominator := denominatorReal*denominatorReal + denominatorImag*denominatorImag
+		if denominator == 0 {
+			break
+		}
+
+		zReal = (numeratorReal*denominatorReal + numeratorImag*denominatorImag) / denominator
+		zImag = (numeratorImag*denominatorReal - numeratorReal*denominatorImag) / denominator
+	}
+
+	// z did not converge within maxIterations
+	return maxIterations, -1
+}
+
+// calculateHalleyPoint calculates which cube root of unity Halley's method
+// for z^3 - 1 converges to from a starting point, and how quickly
+//
+// Parameters:
+//   - real: Real component of the starting point z
+//   - imag: Imaginary component of the starting point z
+//   - maxIterations: Maximum number of iterations to perform
+//   - tolerance: Distance from a root at which z counts as converged
+//
+// Returns:
+//   - An object {iterations, root} as for calculateNewtonPoint. Points that
+//     don't converge within maxIterations return maxIterations and root -1.
+func calculateHalleyPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateHalleyPoint", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	tolerance := r.number(3, "tolerance")
+	r.check(tolerance > 0, "tolerance must be greater than 0, got %v", tolerance)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations, root := halleyConvergence(real, imag, maxIterations, tolerance)
+
+	return map[string]interface{}{
+		"iterations": iterations,
+		"root":       root,
+	}
+}
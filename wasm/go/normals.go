@@ -0,0 +1,131 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Slope shading
+//
+// Treating the smooth iteration count as a height field turns the exterior
+// into a landscape whose ridges follow the filaments. renderNormals returns
+// the surface normal of that field at every pixel, from central differences
+// of the smooth counts of its four neighbors, so a shader or JS loop can
+// light it with a single dot product. The field is computed with a one pixel
+// border around the viewport, so edge pixels get the same central
+// differences as the rest and adjacent tiles shade without seams.
+//
+// Interior points have no smooth count. They are flat, with normal (0, 0),
+// and an interior neighbor is left out of the difference in favor of the
+// one-sided difference against the pixel itself. Along an axis where both
+// neighbors are interior the slope is 0.
+
+// normalChannels is the number of float32 values renderNormals writes per
+// pixel
+const normalChannels = 2
+
+// fieldSlope returns the slope of the smooth count field at a pixel along
+// one axis from the values before and after it, skipping interior neighbors
+func fieldSlope(before, center, after float64) float64 {
+	switch {
+	case before != interiorSmooth && after != interiorSmooth:
+		return (after - before) / 2
+	case after != interiorSmooth:
+		return after - center
+	case before != interiorSmooth:
+		return center - before
+	}
+	return 0
+}
+
+// fillNormals computes the unit normal of the smooth count field at every
+// pixel, storing its x and y components in row-major order in normals
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillNormals(normals []float64, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	// Smooth counts of the viewport and a one pixel border, so pixel (x, y)
+	// is at field[(y+1)*stride+x+1]
+	stride := v.width + 2
+	field := make([]float64, stride*(v.height+2))
+	completedRows := parallelFor(v.height+2, func(startRow, endRow int) int {
+		for row := startRow; row < endRow; row++ {
+			if (row-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return row - startRow
+			}
+
+			for column := 0; column < stride; column++ {
+				smooth, interior := v.smoothAt(column-1, row-1, 0, 0, maxIterations, escapeRadiusSquared)
+				if interior {
+					smooth = interiorSmooth
+				}
+				field[row*stride+column] = smooth
+			}
+		}
+		return endRow - startRow
+	})
+
+	// A pixel row needs the field row below it
+	rows := min(max(completedRows-2, 0), v.height)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < v.width; x++ {
+			i := (y+1)*stride + x + 1
+			var normalX, normalY float64
+			if center := field[i]; center != interiorSmooth {
+				slopeX := fieldSlope(field[i-1], center, field[i+1])
+				slopeY := fieldSlope(field[i-stride], center, field[i+stride])
+				length := math.Sqrt(slopeX*slopeX + slopeY*slopeY + 1)
+				normalX, normalY = -slopeX/length, -slopeY/length
+			}
+			normals[(y*v.width+x)*normalChannels] = normalX
+			normals[(y*v.width+x)*normalChannels+1] = normalY
+		}
+	}
+	return rows * v.width
+}
+
+// renderNormals renders the surface normals of the smooth iteration count
+// field of a viewport, for slope-shaded (fake 3D) lighting
+//
+// The field's height at a pixel is its smooth count and x and y are in
+// pixels, with y increasing downward as on the canvas. The unit normal is
+// (-dh/dx, -dh/dy, 1) / length; its z component is
+// sqrt(1 - normalX^2 - normalY^2) and is not stored.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - normalBuf: Float32Array of at least 2*width*height elements receiving
+//     normalX, normalY per pixel in row-major order; (0, 0) for interior
+//     points
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderNormals(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderNormals", args, 8)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	normalBuf := r.typedArray(7, "normalBuf", "Float32Array")
+	r.minLength(normalBuf, "normalBuf", view.pixelCount()*normalChannels)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	normals := make([]float64, view.pixelCount()*normalChannels)
+	completed := view.fillNormals(normals, maxIterations, escapeThreshold(escapeRadius))
+
+	writeFloat32s(normalBuf, normals[:completed*normalChannels])
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
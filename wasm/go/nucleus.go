@@ -0,0 +1,134 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"syscall/js"
+)
+
+// Minibrot nuclei
+//
+// The nucleus of a hyperbolic component of period p is a parameter c whose
+// orbit returns exactly to 0 after p steps: z_p(c) = 0 with z_0 = 0 and
+// z_(n+1) = z_n^2 + c. Each minibrot and each bulb has one at its center.
+//
+// findNearbyPeriodicPoint first guesses the period from the starting point's
+// orbit: the step p at which |z_p| is smallest over the first maxPeriod steps
+// is the period of the atom domain containing the start, and usually of the
+// nearest visible component. It then solves z_p(c) = 0 by Newton's method,
+// with the derivative dz/dc carried along the orbit by
+// dz_(n+1) = 2*z_n*dz_n + 1.
+
+// nucleusTolerance is the relative step size at which Newton's method counts
+// as converged
+const nucleusTolerance = 1e-15
+
+// findNearbyPeriodicPoint locates the nucleus of a hyperbolic component near
+// a starting point, for snapping a deep zoom to the center of a minibrot
+//
+// Parameters:
+//   - startReal, startImag: Starting guess, typically the view center
+//   - maxPeriod: Largest period to consider, from 1 to the limit set with
+//     setMaxIterationsLimit
+//   - maxIterations: Maximum number of Newton steps
+//
+// Returns:
+//   - An object {found: true, real, imag, period} with the nucleus and its
+//     period, or {found: false} when no period could be guessed or Newton's
+//     method did not converge within maxIterations. {error} for invalid
+//     arguments.
+func findNearbyPeriodicPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("findNearbyPeriodicPoint", args, 4)
+	startReal := r.number(0, "startReal")
+	startImag := r.number(1, "startImag")
+	maxPeriod := int(r.iterationCount(2, "maxPeriod"))
+	maxIterations := r.maxIterations(3)
+	r.check(isFinite(startReal, startImag), "the starting point must be finite, got %v%+vi", startReal, startImag)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	start := complex(startReal, startImag)
+	period := atomDomainPeriod(start, maxPeriod)
+	if period == 0 {
+		return map[string]interface{}{"found": false}
+	}
+	nucleus, ok := newtonNucleus(start, period, maxIterations)
+	if !ok {
+		return map[string]interface{}{"found": false}
+	}
+
+	return map[string]interface{}{
+		"found":  true,
+		"real":   real(nucleus),
+		"imag":   imag(nucleus),
+		"period": lowestPeriod(nucleus, period),
+	}
+}
+
+// atomDomainPeriod returns the step p in [1, maxPeriod] at which the orbit of
+// c comes closest to 0, stopping at escape, or 0 if c itself escapes at once
+func atomDomainPeriod(c complex128, maxPeriod int) int {
+	period := 0
+	closest := math.Inf(1)
+	z := complex(0, 0)
+	for p := 1; p <= maxPeriod; p++ {
+		z = z*z + c
+		magnitudeSquared := real(z)*real(z) + imag(z)*imag(z)
+		if magnitudeSquared > 4 {
+			break
+		}
+		if magnitudeSquared < closest {
+			closest = magnitudeSquared
+			period = p
+		}
+	}
+	return period
+}
+
+// newtonNucleus solves z_period(c) = 0 by Newton's method from start
+//
+// Returns the root and true once a step is smaller than nucleusTolerance
+// relative to |c|, or false if that doesn't happen within maxIterations
+// steps or the iteration leaves the finite range.
+func newtonNucleus(start complex128, period int, maxIterations uint32) (complex128, bool) {
+	c := start
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		z, dz := complex(0, 0), complex(0, 0)
+		for n := 0; n < period; n++ {
+			z, dz = z*z+c, 2*z*dz+1
+		}
+
+		step := z / dz
+		if cmplx.IsNaN(step) || cmplx.IsInf(step) {
+			return 0, false
+		}
+		c -= step
+		if cmplx.Abs(step) <= nucleusTolerance*math.Max(1, cmplx.Abs(c)) {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// lowestPeriod returns the smallest divisor d of period with z_d(c) within
+// rounding of 0, which is the true period of a nucleus found for a multiple
+// of it
+func lowestPeriod(c complex128, period int) int {
+	orbit := make([]complex128, period+1)
+	for n := 1; n <= period; n++ {
+		orbit[n] = orbit[n-1]*orbit[n-1] + c
+	}
+
+	// z_period is within a few rounding errors of 0 at a converged nucleus;
+	// lower periods are accepted when they come as close
+	threshold := math.Max(cmplx.Abs(orbit[period]), 1e-12*math.Max(1, cmplx.Abs(c)))
+	for d := 1; d < period; d++ {
+		if period%d == 0 && cmplx.Abs(orbit[d]) <= threshold {
+			return d
+		}
+	}
+	return period
+}
@@ -0,0 +1,299 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Single-point functions that report details of the orbit beyond the escape
+// iteration, for coloring schemes that need more than a count.
+
+// calculatePointWithMagnitude calculates the escape iteration of a Mandelbrot
+// point together with the squared orbit magnitude at that moment
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - An object {iterations, magnitudeSquared}. For points that don't escape,
+//     iterations is maxIterations and magnitudeSquared is |z|^2 after the last iteration.
+func calculatePointWithMagnitude(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculatePointWithMagnitude", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// The cardioid shortcut is skipped here since it can't report a magnitude
+	iterations, zMagnitudeSquared := escapeTime(0, 0, real, imag, maxIterations, escapeThreshold(escapeRadius))
+
+	return map[string]interface{}{
+		"iterations":       iterations,
+		"magnitudeSquared": zMagnitudeSquared,
+	}
+}
+
+// calculatePointWithPhase calculates the escape iteration of a Mandelbrot
+// point together with the argument of z at that moment, for domain coloring
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - An object {iterations, phase} where phase is atan2(zImag, zReal) of the
+//     first orbit value outside the escape radius, in [-pi, pi]. For points
+//     that don't escape, iterations is maxIterations and phase is NaN. A NaN
+//     or infinite point escapes at iteration 0 with phase NaN.
+func calculatePointWithPhase(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculatePointWithPhase", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	var lastReal, lastImag float64
+	iterations, _ := walkOrbit(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) bool {
+		lastReal, lastImag = zReal, zImag
+		return true
+	})
+
+	phase := math.NaN()
+	if iterations > 0 && iterations < maxIterations {
+		phase = math.Atan2(lastImag, lastReal)
+	}
+	return map[string]interface{}{
+		"iterations": iterations,
+		"phase":      phase,
+	}
+}
+
+// calculatePotential calculates the continuous potential of a Mandelbrot
+// point, log|z_n| / 2^n at the escape iteration n
+//
+// This approximates the Green's function of the set's complement, the
+// electrostatic potential around a charged set: it falls smoothly to 0 at
+// the boundary, and shading by it gives an embossed look. The approximation
+// improves with the escape radius; radii of 100 or more make it close to
+// exact.
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The potential as a float64 for points that escape, positive for escape
+//     radii above 1, or 0 for points that don't escape within maxIterations
+func calculatePotential(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculatePotential", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+	if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(real, imag) {
+		return 0.0
+	}
+
+	iterations, zMagnitudeSquared := escapeTime(0, 0, real, imag, maxIterations, escapeRadiusSquared)
+	if iterations >= maxIterations {
+		return 0.0
+	}
+
+	// log|z| = log(|z|^2)/2; Ldexp divides by 2^n without overflowing for
+	// large n
+	return math.Ldexp(math.Log(zMagnitudeSquared)/2, -int(iterations))
+}
+
+// walkOrbit iterates z = z^2 + c from z = 0 like escapeTime and calls visit
+// with each new z after it is computed (z_1, z_2, ...), up to and including
+// the value that escapes. Returning false from visit stops the walk early.
+//
+// Returns the same iteration count and squared magnitude as escapeTime with
+// the circle bailout, or for a walk stopped by visit, the number of orbit
+// values visited and the squared magnitude of the last one. A NaN or infinite
+// c escapes at iteration 0 with an infinite magnitude and no values visited,
+// as in escapeTime.
+//
+// The square bailout selected with setBailoutShape is not applied: the
+// distance estimate, stripe average and linear escape time built on the walk
+// assume the orbit leaves a circle, so its functions always use the circle.
+func walkOrbit(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64, visit func(zReal, zImag float64) bool) (uint32, float64) {
+	if !isFinite(cReal, cImag) {
+		return 0, math.Inf(1)
+	}
+
+	zReal := 0.0
+	zImag := 0.0
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared
+		}
+
+		// Calculate z = z^2 + c
+		// (a + bi)^2 = a^2 - b^2 + 2abi
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+
+		if !visit(zReal, zImag) {
+			return iteration + 1, zReal*zReal + zImag*zImag
+		}
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
+
+// calculateOrbit returns the trajectory of a point's Mandelbrot orbit, for
+// drawing it over the canvas
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - maxPoints: Maximum number of orbit points to return
+//
+// Returns:
+//   - A flat array [zReal1, zImag1, zReal2, zImag2, ...] of the orbit values
+//     z_1 = c, z_2, ... as computed by walkOrbit. It ends at the first value
+//     outside the escape radius, after maxIterations values, or after
+//     maxPoints values, whichever comes first.
+func calculateOrbit(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateOrbit", args, 5)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	maxPoints := r.positiveInteger(4, "maxPoints")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	points := make([]interface{}, 0, 2*min(maxPoints, int(maxIterations)))
+	walkOrbit(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) bool {
+		points = append(points, zReal, zImag)
+		return len(points) < 2*maxPoints
+	})
+
+	return js.ValueOf(points)
+}
+
+// calculateLinearEscapeTime calculates a fractional escape time by locating
+// where the orbit crosses the escape radius
+//
+// If z_n is the first orbit value outside the escape radius, the result is
+// (n - 1) + (escapeRadius - |z_{n-1}|) / (|z_n| - |z_{n-1}|): the step at
+// which the magnitude, interpolated linearly between the last value inside
+// and the first outside, reaches the radius. Unlike the log-log smoothing of
+// calculatePoint, it depends only on the two values nearest the crossing, so
+// colors stay stable as the view moves.
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The escape time in (n - 1, n] for a point escaping at iteration n, or -1
+//     for points that don't escape within maxIterations. A NaN or infinite
+//     point returns 0. {error} for invalid arguments.
+func calculateLinearEscapeTime(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateLinearEscapeTime", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// z_0 = 0, so the magnitude before z_1 is 0
+	insideMagnitude := 0.0
+	lastMagnitude := 0.0
+	iterations, _ := walkOrbit(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) bool {
+		insideMagnitude = lastMagnitude
+		lastMagnitude = math.Hypot(zReal, zImag)
+		return true
+	})
+
+	if iterations == maxIterations {
+		return -1.0
+	}
+	if iterations == 0 {
+		return 0.0
+	}
+	return float64(iterations-1) + (escapeRadius-insideMagnitude)/(lastMagnitude-insideMagnitude)
+}
+
+// calculateLemniscateLevel iterates a point's orbit a fixed number of times
+// and returns the magnitude reached, for contouring the lemniscates
+// |z_n| = escapeRadius of the Mandelbrot set
+//
+// Unlike the escape-time functions the orbit never stops early, so the
+// result is a continuous function of c whose level set at escapeRadius is
+// the n-th lemniscate. Orbits that grow past float64 range give +Inf.
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - n: Number of iterations, from 0 to the limit set with
+//     setMaxIterationsLimit
+//   - escapeRadius: The level R of the lemniscate, greater than 0; points
+//     with a result of at most R lie inside it
+//
+// Returns:
+//   - |z_n|, or {error} for invalid arguments
+func calculateLemniscateLevel(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateLemniscateLevel", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	n := 0
+	if r.number(2, "n") != 0 {
+		n = int(r.iterationCount(2, "n"))
+	}
+	r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	zReal, zImag := 0.0, 0.0
+	for iteration := 0; iteration < n; iteration++ {
+		// Once |z|^2 overflows, every later orbit value does too
+		if zReal*zReal+zImag*zImag > math.MaxFloat64 {
+			return math.Inf(1)
+		}
+
+		zRealTemp := zReal*zReal - zImag*zImag + real
+		zImag = 2.0*zReal*zImag + imag
+		zReal = zRealTemp
+	}
+
+	return math.Hypot(zReal, zImag)
+}
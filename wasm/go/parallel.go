@@ -0,0 +1,166 @@
+//go:build js
+
+package main
+
+import (
+	"runtime"
+	"sync"
+	"syscall/js"
+)
+
+// Parallel batch computation
+//
+// Work is split into one contiguous chunk per worker goroutine and joined
+// with a sync.WaitGroup. Each worker writes only its own chunk of the result
+// slice, so results stay in input order without any locking.
+//
+// The standard Go and TinyGo wasm ports currently run every goroutine on the
+// single JS thread, so on today's browsers the workers execute one after
+// another and the split gives no speedup; it costs a few goroutine switches
+// per call. The results are identical for any worker count. The split is in
+// place for runtimes that schedule goroutines on multiple threads; to use
+// several cores today, run one module instance per Web Worker.
+
+// workerCount is the number of goroutines batch and viewport renders use
+var workerCount = defaultWorkerCount()
+
+// defaultWorkerCount returns the number of CPUs the Go runtime reports,
+// which is 1 on the current wasm ports
+func defaultWorkerCount() int {
+	if n := runtime.NumCPU(); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// setWorkerCount sets how many goroutines batch and viewport renders split
+// their work across
+//
+// Parameters:
+//   - n: Number of workers, at least 1
+//
+// Returns:
+//   - true when the count was applied, {error} for counts below 1
+func setWorkerCount(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setWorkerCount", args, 1)
+	n := r.positiveInteger(0, "n")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	workerCount = n
+	return true
+}
+
+// getConcurrency reports the parallelism available to the Go runtime, for
+// sizing setWorkerCount or choosing how many Web Workers to spawn
+//
+// Returns:
+//   - runtime.NumCPU(). The current wasm ports report 1, since the module
+//     runs on a single thread; each Web Worker runs its own instance.
+func getConcurrency(this js.Value, args []js.Value) interface{} {
+	return runtime.NumCPU()
+}
+
+// parallelFor splits [0, n) into contiguous chunks, one per worker, and runs
+// body on each chunk concurrently
+//
+// body receives the chunk bounds [start, end) and returns how many leading
+// indices of its chunk it completed, which is less than end-start only when
+// the render was cancelled. parallelFor returns the length of the completed
+// prefix of [0, n): every index below it has been computed.
+func parallelFor(n int, body func(start, end int) int) int {
+	workers := workerCount
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n <= 0 {
+			return 0
+		}
+		return body(0, n)
+	}
+
+	chunkSize := (n + workers - 1) / workers
+	workers = (n + chunkSize - 1) / chunkSize
+	completed := make([]int, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			completed[w] = body(start, end)
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	// Count chunks in order until the first one that stopped early
+	prefix := 0
+	for w := 0; w < workers; w++ {
+		prefix += completed[w]
+		if completed[w] < chunkSize && prefix < n {
+			break
+		}
+	}
+	return prefix
+}
+
+// computeBatch applies pointFn to every coordinate pair in parallel
+//
+// Returns the results and the number of leading results that were completed,
+// which is less than len(results) only when the render was cancelled.
+func computeBatch(realCoords, imagCoords []float64, pointFn func(real, imag float64) uint32) ([]uint32, int) {
+	// Use minimum length to handle mismatched arrays
+	length := len(realCoords)
+	if len(imagCoords) < length {
+		length = len(imagCoords)
+	}
+
+	results := make([]uint32, length)
+
+	completed := parallelFor(length, func(start, end int) int {
+		for i := start; i < end; i++ {
+			if (i-start)%pointsPerCancelCheck == 0 && isRenderCancelled() {
+				return i - start
+			}
+			results[i] = pointFn(realCoords[i], imagCoords[i])
+		}
+		return end - start
+	})
+
+	return results, completed
+}
+
+// computeMandelbrotBatch computes the Mandelbrot iteration count of every
+// coordinate pair in parallel with mandelbrotEscapeTimes
+//
+// Returns the same values as computeBatch with Iterate.
+func computeMandelbrotBatch(realCoords, imagCoords []float64, maxIterations uint32, escapeRadiusSquared float64) ([]uint32, int) {
+	// Use minimum length to handle mismatched arrays
+	length := len(realCoords)
+	if len(imagCoords) < length {
+		length = len(imagCoords)
+	}
+
+	results := make([]uint32, length)
+
+	completed := parallelFor(length, func(start, end int) int {
+		for blockStart := start; blockStart < end; blockStart += pointsPerCancelCheck {
+			if isRenderCancelled() {
+				return blockStart - start
+			}
+			blockEnd := min(blockStart+pointsPerCancelCheck, end)
+			mandelbrotEscapeTimes(results[blockStart:blockEnd], realCoords[blockStart:blockEnd], imagCoords[blockStart:blockEnd], maxIterations, escapeRadiusSquared)
+		}
+		return end - start
+	})
+
+	return results, completed
+}
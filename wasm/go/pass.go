@@ -0,0 +1,141 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// renderPass identifies a progressive render so that a finer pass can tell
+// which of its pixels an earlier, coarser pass already computed
+type renderPass struct {
+	view                viewport
+	maxIterations       uint32
+	escapeRadiusSquared float64
+	highPrecision       bool
+	fixedPoint          bool
+	offsetX             int
+	offsetY             int
+}
+
+// lastPass is the most recent completed pass and lastPassStride its stride,
+// or 0 when there is none
+var (
+	lastPass       renderPass
+	lastPassStride = 0
+)
+
+// fillPass computes the pixels (x, y) with x = offsetX + i*stride and
+// y = offsetY + j*stride, skipping those that also lie on the grid of
+// skipStride (0 to skip none)
+//
+// Returns the number of pixels computed and whether the pass completed.
+func (v viewport) fillPass(results []uint32, stride, offsetX, offsetY, skipStride int, maxIterations uint32, escapeRadiusSquared float64) (int, bool) {
+	passRows := 0
+	if offsetY < v.height {
+		passRows = (v.height - offsetY + stride - 1) / stride
+	}
+	counts := make([]int, passRows)
+
+	completedRows := parallelFor(passRows, func(startRow, endRow int) int {
+		for row := startRow; row < endRow; row++ {
+			if (row-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return row - startRow
+			}
+
+			y := offsetY + row*stride
+			skipRow := skipStride > 0 && (y-offsetY)%skipStride == 0
+			for x := offsetX; x < v.width; x += stride {
+				if skipRow && (x-offsetX)%skipStride == 0 {
+					continue
+				}
+				results[y*v.width+x], _ = v.escapeTimeAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+				counts[row]++
+			}
+		}
+		return endRow - startRow
+	})
+
+	// Cancelled workers may have finished rows past the completed prefix, so
+	// every row's count is included
+	computed := 0
+	for _, count := range counts {
+		computed += count
+	}
+	return computed, completedRows == passRows
+}
+
+// renderViewportPass computes one pass of a progressive viewport render,
+// filling only the pixels on a stride grid
+//
+// A typical render calls this with stride 8, then 4, 2 and 1 using the same
+// viewport, offsets and buffer, showing a sharper preview after each pass.
+// When a call continues the previous completed pass (same view and offsets,
+// smaller stride that divides the previous one), pixels that pass already
+// computed are left as they are in resultBuf instead of being recomputed.
+// Pixels off the stride grid are never written.
+//
+// Parameters:
+//   - width: Viewport width in pixels
+//   - height: Viewport height in pixels
+//   - centerReal: Real component at the center of the viewport
+//   - centerImag: Imaginary component at the center of the viewport
+//   - scale: Complex-plane units per pixel
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - stride: Spacing of the pass grid in pixels, at least 1
+//   - offsetX: Column of the first grid pixel, in [0, stride)
+//   - offsetY: Row of the first grid pixel, in [0, stride)
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//
+// Returns:
+//   - The number of pixels computed in this pass, or {error} if the arguments
+//     or buffer are invalid. If cancelRender stops the render, an object
+//     {written, cancelled: true} where written counts the pixels that were
+//     computed; the next pass then starts afresh.
+func renderViewportPass(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderViewportPass", args, 11)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	stride := r.positiveInteger(7, "stride")
+	offsetX := r.integer(8, "offsetX")
+	offsetY := r.integer(9, "offsetY")
+	resultBuf := r.typedArray(10, "resultBuf", "Uint32Array")
+	r.check(offsetX >= 0 && offsetX < stride, "offsetX must be in [0, %d), got %d", stride, offsetX)
+	r.check(offsetY >= 0 && offsetY < stride, "offsetY must be in [0, %d), got %d", stride, offsetY)
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	pass := renderPass{
+		view:                view,
+		maxIterations:       maxIterations,
+		escapeRadiusSquared: escapeThreshold(escapeRadius),
+		highPrecision:       highPrecision,
+		fixedPoint:          fixedPoint,
+		offsetX:             offsetX,
+		offsetY:             offsetY,
+	}
+
+	skipStride := 0
+	if pass == lastPass && lastPassStride > stride && lastPassStride%stride == 0 {
+		skipStride = lastPassStride
+	}
+
+	beginRender()
+	results := readUint32s(resultBuf)[:view.pixelCount()]
+	computed, ok := view.fillPass(results, stride, offsetX, offsetY, skipStride, maxIterations, pass.escapeRadiusSquared)
+	writeUint32s(resultBuf, results)
+
+	if !ok {
+		lastPassStride = 0
+		return cancelledResult(computed)
+	}
+
+	lastPass = pass
+	lastPassStride = stride
+	return computed
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+)
+
+// defaultPeriodicityEpsilon is the per-component distance below which two
+// orbit samples are considered equal. 1e-10 is far above float64 rounding
+// noise for orbits bounded by |z| <= 2, yet small enough that slowly escaping
+// points near the boundary are not mistaken for cycles at typical zoom levels.
+const defaultPeriodicityEpsilon = 1e-10
+
+// Periodicity checking settings, changed from JavaScript via setPeriodicityCheck.
+// Disabled by default so escape iterations are exact unless a caller opts in.
+var (
+	periodicityCheck   = false
+	periodicityEpsilon = defaultPeriodicityEpsilon
+)
+
+// escapeTimePeriodic is escapeTime with cycle detection
+//
+// A reference point is saved from the orbit and each new z is compared with
+// it. The distance between saves doubles each time (Brent's method), so cycles
+// of any period are eventually caught without storing the orbit. When z comes
+// back within periodicityEpsilon of the reference, the orbit is periodic and
+// can never escape, so maxIterations is returned immediately.
+func escapeTimePeriodic(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	iterations, zMagnitudeSquared, _ := periodicEscapeTime(zReal, zImag, cReal, cImag, maxIterations, escapeRadiusSquared)
+	return iterations, zMagnitudeSquared
+}
+
+// periodicEscapeTime is escapeTimePeriodic also reporting whether a cycle
+// was detected, which proves the point interior
+func periodicEscapeTime(zReal, zImag, cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64, bool) {
+	referenceReal := zReal
+	referenceImag := zImag
+	samplesUntilSave := 1
+	saveInterval := 1
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared, false
+		}
+
+		// Calculate z = z^2 + c
+		// (a + bi)^2 = a^2 - b^2 + 2abi
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+
+		// Check if the orbit has returned to the reference point
+		if math.Abs(zReal-referenceReal) < periodicityEpsilon && math.Abs(zImag-referenceImag) < periodicityEpsilon {
+			return maxIterations, zReal*zReal + zImag*zImag, true
+		}
+
+		samplesUntilSave--
+		if samplesUntilSave == 0 {
+			referenceReal = zReal
+			referenceImag = zImag
+			saveInterval *= 2
+			samplesUntilSave = saveInterval
+		}
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag, false
+}
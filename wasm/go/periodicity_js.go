@@ -0,0 +1,37 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// setPeriodicityCheck enables or disables cycle detection for all escape-time
+// calculations
+//
+// Parameters:
+//   - enabled: When true, orbits that return to a previously saved point are
+//     treated as interior and stop iterating early
+//   - epsilon (optional): Per-component match tolerance, a positive finite
+//     number defaulting to 1e-10
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setPeriodicityCheck(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setPeriodicityCheck", args, 1, 2)
+	enabled := r.value(0).Truthy()
+
+	epsilon := defaultPeriodicityEpsilon
+	if r.has(1) {
+		epsilon = r.number(1, "epsilon")
+		r.check(epsilon > 0 && !math.IsInf(epsilon, 1), "epsilon must be a positive finite number, got %v", epsilon)
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	periodicityCheck = enabled
+	periodicityEpsilon = epsilon
+	return true
+}
@@ -0,0 +1,183 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// glitchTolerance is the Pauldelbrot criterion threshold: a pixel is
+// glitched once |Z + dz| < glitchTolerance * |Z|, because its orbit has come
+// so close to zero relative to the reference that the float64 delta no
+// longer carries enough significant bits
+const glitchTolerance = 1e-3
+
+// referenceOrbit computes the orbit Z_0 = 0, Z_1, ... of c in double-double
+// precision, rounded to float64 for use by the perturbed pixels
+//
+// The orbit stops at the first value outside the escape radius (which is
+// included) or after maxIterations values.
+func referenceOrbit(cReal, cImag doubleDouble, maxIterations uint32, escapeRadiusSquared float64) ([]float64, []float64) {
+	orbitReal := make([]float64, 0, maxIterations)
+	orbitImag := make([]float64, 0, maxIterations)
+	var zReal, zImag doubleDouble
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		orbitReal = append(orbitReal, zReal.hi)
+		orbitImag = append(orbitImag, zImag.hi)
+
+		zRealSquared := zReal.mul(zReal)
+		zImagSquared := zImag.mul(zImag)
+		if zRealSquared.hi+zImagSquared.hi > escapeRadiusSquared {
+			break
+		}
+
+		// Z = Z^2 + c
+		zImag = zReal.mul(zImag).double().add(cImag)
+		zReal = zRealSquared.sub(zImagSquared).add(cReal)
+	}
+
+	return orbitReal, orbitImag
+}
+
+// perturbedEscapeTime iterates the offset dz of a pixel from the reference
+// orbit, dz' = 2*Z*dz + dz^2 + dc, stopping when Z + dz escapes. The loop
+// starts at the iteration and offset given by series, which is the zero
+// value when series approximation is off.
+//
+// Returns the iteration count and whether the pixel is glitched, either by
+// the Pauldelbrot criterion or because the reference orbit escaped before the
+// pixel did. The count of a glitched pixel is the iteration at which the
+// glitch was detected.
+func perturbedEscapeTime(orbitReal, orbitImag []float64, series seriesCoefficients, dcReal, dcImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, bool) {
+	const toleranceSquared = glitchTolerance * glitchTolerance
+	dz := series.evaluate(complex(dcReal, dcImag))
+	dzReal, dzImag := real(dz), imag(dz)
+
+	for iteration := series.skip; iteration < maxIterations; iteration++ {
+		if int(iteration) >= len(orbitReal) {
+			return iteration, true
+		}
+
+		refReal := orbitReal[iteration]
+		refImag := orbitImag[iteration]
+		zReal := refReal + dzReal
+		zImag := refImag + dzImag
+
+		magnitudeSquared := zReal*zReal + zImag*zImag
+		if magnitudeSquared > escapeRadiusSquared {
+			return iteration, false
+		}
+		if magnitudeSquared < toleranceSquared*(refReal*refReal+refImag*refImag) {
+			return iteration, true
+		}
+
+		// dz = 2*Z*dz + dz^2 + dc
+		dzRealTemp := 2*(refReal*dzReal-refImag*dzImag) + dzReal*dzReal - dzImag*dzImag + dcReal
+		dzImag = 2*(refReal*dzImag+refImag*dzReal) + 2*dzReal*dzImag + dcImag
+		dzReal = dzRealTemp
+	}
+
+	// Pixel did not escape within maxIterations
+	return maxIterations, false
+}
+
+// fillPerturbation is fillEscapeTimes using perturbation around a reference
+// orbit at the viewport center, marking glitched pixels with 1 in glitches
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillPerturbation(results []uint32, glitches []byte, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	orbitReal, orbitImag := referenceOrbit(
+		doubleDouble{hi: v.centerReal},
+		doubleDouble{hi: v.centerImag},
+		maxIterations,
+		escapeRadiusSquared,
+	)
+
+	var series seriesCoefficients
+	if seriesApproximation {
+		series = approximateSeries(orbitReal, orbitImag, v.maxOffset(), escapeRadiusSquared)
+	}
+
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			dcImag := -(float64(y) - float64(v.height)/2) * v.scaleY
+			for x := 0; x < v.width; x++ {
+				dcReal := (float64(x) - float64(v.width)/2) * v.scaleX
+				iterations, glitched := perturbedEscapeTime(orbitReal, orbitImag, series, dcReal, dcImag, maxIterations, escapeRadiusSquared)
+
+				index := y*v.width + x
+				results[index] = iterations
+				glitches[index] = 0
+				if glitched {
+					glitches[index] = 1
+				}
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
+
+// renderPerturbation renders a viewport like renderViewport, but computes a
+// single double-double reference orbit at the center and iterates each
+// pixel's offset from it in float64
+//
+// Pixel offsets are only ever relative to the center, so they keep full
+// float64 precision at scales far below what absolute coordinates allow.
+// Pixels whose offsets lose precision are detected with Pauldelbrot's
+// criterion and flagged so the caller can re-render them around a new
+// reference.
+//
+// Parameters:
+//   - width: Viewport width in pixels
+//   - height: Viewport height in pixels
+//   - centerReal: Real component at the center of the viewport
+//   - centerImag: Imaginary component at the center of the viewport
+//   - scale: Complex-plane units per pixel
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//   - glitchBuf: Uint8Array of at least width*height elements receiving 1 for
+//     glitched pixels and 0 for all others
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffers
+//     are invalid. If cancelRender stops the render, an object
+//     {written, cancelled: true} where written counts the leading pixels that
+//     were filled in both buffers.
+func renderPerturbation(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderPerturbation", args, 9)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	resultBuf := r.typedArray(7, "resultBuf", "Uint32Array")
+	glitchBuf := r.typedArray(8, "glitchBuf", "Uint8Array")
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	r.minLength(glitchBuf, "glitchBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results := make([]uint32, view.pixelCount())
+	glitches := make([]byte, view.pixelCount())
+	completed := view.fillPerturbation(results, glitches, maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf, results[:completed])
+	js.CopyBytesToJS(glitchBuf, glitches[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
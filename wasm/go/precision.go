@@ -0,0 +1,95 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Precision checking
+//
+// A pixel can only be resolved while the step to its neighbour is large
+// compared with the rounding unit of its coordinates. Beyond that,
+// neighbouring pixels round to the same point or to points a few ulps apart,
+// and the image turns into blocks and noise. Relative to the larger
+// component of the coordinate, float64 gives out at a step of about 1e-15
+// and double-double at about 1e-30; fixed point has an absolute resolution,
+// which stops zooms at a step of about 1e-17.
+
+// Smallest per-pixel steps, relative to the coordinate magnitude for
+// floating point and absolute for fixed point, that each arithmetic mode
+// still resolves
+const (
+	float64PrecisionLimit      = 1e-15
+	doubleDoublePrecisionLimit = 1e-30
+	fixedPointPrecisionLimit   = 1e-17
+)
+
+// lowPrecisionAt reports whether pixel (x, y) is too small a step for the
+// arithmetic the viewport renderers use in the current mode
+func (v viewport) lowPrecisionAt(x, y int) bool {
+	step := math.Min(v.scaleX, v.scaleY)
+	if fixedPoint {
+		return step < fixedPointPrecisionLimit
+	}
+
+	cReal, cImag := v.pointAt(x, y)
+	limit := float64PrecisionLimit
+	if highPrecision {
+		limit = doubleDoublePrecisionLimit
+	}
+	return step < limit*math.Max(math.Abs(cReal), math.Abs(cImag))
+}
+
+// checkPrecision reports which pixels of a viewport the current arithmetic
+// mode can no longer resolve, so an app can prompt for high precision instead
+// of showing a degraded image
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - flagBuf (optional): Uint8Array of at least width*height elements
+//     receiving 1 for low-precision pixels and 0 for all others in row-major
+//     order
+//
+// Returns:
+//   - An object {lowPrecision, flagged} where flagged counts the
+//     low-precision pixels and lowPrecision is true when there are any, or
+//     {error} if the arguments or buffer are invalid
+func checkPrecision(this js.Value, args []js.Value) interface{} {
+	r := readArgs("checkPrecision", args, 5, 6)
+	view := r.viewport(0)
+	var flagBuf js.Value
+	if r.has(5) {
+		flagBuf = r.typedArray(5, "flagBuf", "Uint8Array")
+		r.minLength(flagBuf, "flagBuf", view.pixelCount())
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// Flags are only stored when the caller wants them back
+	var flags []byte
+	if r.has(5) {
+		flags = make([]byte, view.pixelCount())
+	}
+	flagged := 0
+	for y := 0; y < view.height; y++ {
+		for x := 0; x < view.width; x++ {
+			if view.lowPrecisionAt(x, y) {
+				if flags != nil {
+					flags[y*view.width+x] = 1
+				}
+				flagged++
+			}
+		}
+	}
+
+	if flags != nil {
+		js.CopyBytesToJS(flagBuf, flags)
+	}
+	return map[string]interface{}{
+		"lowPrecision": flagged > 0,
+		"flagged":      flagged,
+	}
+}
@@ -0,0 +1,85 @@
+//go:build js
+
+package main
+
+import (
+	"sync/atomic"
+	"syscall/js"
+)
+
+// Render progress reporting
+//
+// renderViewport can be given a JS callback that is invoked with the fraction
+// of rows completed so far. The callback runs synchronously, inside the
+// renderViewport call and on the JS thread: the page doesn't repaint and no
+// other JS (timers, events) runs until the render returns, so a progress bar
+// updated from it only appears at the end unless the render is split into
+// smaller calls. What the callback can do mid-render is record the value,
+// post it to another thread with postMessage when the render runs in a Web
+// Worker, or stop the render by calling cancelRender.
+
+// defaultProgressRows is how many rows are completed between progress
+// reports when renderViewport isn't given a progressRows argument
+const defaultProgressRows = 16
+
+// progressReporter calls a JS function with the fraction of a render's rows
+// that are done, every few rows
+type progressReporter struct {
+	callback js.Value
+	every    int
+	total    int
+	done     atomic.Int64
+}
+
+// renderProgress is the reporter of the render in progress, or nil when it
+// doesn't report progress
+var renderProgress *progressReporter
+
+// progress returns a reporter for the optional callback at index and the
+// optional report interval in rows at index+1, or nil when no callback was
+// passed
+//
+// A null or undefined callback counts as none, and its interval isn't read,
+// so later optional arguments can be passed without reporting progress.
+func (r *argReader) progress(index int) *progressReporter {
+	if !r.has(index) {
+		return nil
+	}
+	callback := r.value(index)
+	if r.failed() || callback.IsNull() || callback.IsUndefined() {
+		return nil
+	}
+	if callback.Type() != js.TypeFunction {
+		r.fail("onProgress must be a function, got %s", callback.Type())
+		return nil
+	}
+
+	every := defaultProgressRows
+	if r.has(index + 1) {
+		every = r.positiveInteger(index+1, "progressRows")
+	}
+	return &progressReporter{callback: callback, every: every}
+}
+
+// begin starts reporting for a render of total rows; it does nothing on a
+// nil reporter
+func (p *progressReporter) begin(total int) {
+	if p == nil {
+		return
+	}
+	p.total = total
+	p.done.Store(0)
+}
+
+// rowDone records a completed row, calling the callback when the count
+// reaches a multiple of every or the last row; it does nothing on a nil
+// reporter
+func (p *progressReporter) rowDone() {
+	if p == nil {
+		return
+	}
+	done := int(p.done.Add(1))
+	if done%p.every == 0 || done == p.total {
+		p.callback.Invoke(float64(done) / float64(p.total))
+	}
+}
@@ -0,0 +1,142 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"syscall/js"
+)
+
+// External rays
+//
+// The external ray of angle theta (in turns) is the curve of parameters c
+// whose Boettcher coordinate phi(c) has argument 2*pi*theta. Far from the set
+// phi(c) is close to c, and in general phi(c) = lim z_n(c)^(1/2^(n-1)), so
+// the ray is traced inward by solving z_n(c) = r * e^(2*pi*i*theta*2^(n-1))
+// with Newton's method for a sequence of targets that approach the set.
+//
+// traceExternalRay follows the standard scheme: it starts at radius
+// rayEscapeRadius on the ray, and each doubling of depth takes one more step
+// of the orbit, doubles the angle and moves raySharpness targets inward, from
+// radius rayEscapeRadius towards its square root. Each Newton solve starts
+// from the previous point, which is close enough to converge in a few steps.
+//
+// The angle is doubled exactly in float64, so after about 53 doublings it has
+// no bits left and the trace continues along the ray of angle 0 of the
+// remaining orbit. The points themselves approach the set about as fast as the
+// potential halves, so float64 precision in c runs out at a similar depth.
+
+const (
+	// rayEscapeRadius is the radius at which tracing starts
+	rayEscapeRadius = 65536.0
+
+	// raySharpness is the number of ray points per doubling of depth
+	raySharpness = 4
+
+	// rayTolerance is the relative Newton step at which a ray point is
+	// considered converged
+	rayTolerance = 1e-15
+
+	// maxRayDepth is the largest depth traceExternalRay accepts, a little
+	// beyond the 53 doublings a float64 angle can follow
+	maxRayDepth = 64
+)
+
+// externalRayPoints traces the external ray of angle (in turns, in [0, 1))
+// for depth doublings, taking at most maxIterations Newton steps per point
+//
+// Returns the points from the outermost inward, stopping early if Newton's
+// method leaves the finite plane.
+func externalRayPoints(angle float64, depth int, maxIterations uint32) []complex128 {
+	c := cmplx.Rect(rayEscapeRadius, 2*math.Pi*angle)
+	points := make([]complex128, 1, 1+depth*raySharpness)
+	points[0] = c
+
+	for k := 0; k < depth; k++ {
+		for j := 0; j < raySharpness; j++ {
+			radius := math.Pow(rayEscapeRadius, math.Pow(0.5, (float64(j)+0.5)/raySharpness))
+			target := cmplx.Rect(radius, 2*math.Pi*angle)
+
+			next, ok := solveRayPoint(c, target, k+1, maxIterations)
+			if !ok {
+				return points
+			}
+			c = next
+			points = append(points, c)
+		}
+
+		angle *= 2
+		if angle >= 1 {
+			angle--
+		}
+	}
+	return points
+}
+
+// solveRayPoint solves z_steps(c) = target by Newton's method from start,
+// with dz/dc carried along the orbit by dz_(n+1) = 2*z_n*dz_n + 1
+//
+// Returns false if a step is not finite.
+func solveRayPoint(start, target complex128, steps int, maxIterations uint32) (complex128, bool) {
+	c := start
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		z, dz := complex(0, 0), complex(0, 0)
+		for n := 0; n < steps; n++ {
+			z, dz = z*z+c, 2*z*dz+1
+		}
+
+		step := (z - target) / dz
+		if cmplx.IsNaN(step) || cmplx.IsInf(step) {
+			return 0, false
+		}
+		c -= step
+		if cmplx.Abs(step) <= rayTolerance*math.Max(1, cmplx.Abs(c)) {
+			break
+		}
+	}
+	return c, true
+}
+
+// traceExternalRay traces the external ray of the Mandelbrot set at an angle,
+// for drawing rays and locating where they land
+//
+// Parameters:
+//   - angle: Angle of the ray in turns; values outside [0, 1) are reduced
+//     modulo 1
+//   - depth: Number of angle doublings to trace, from 1 to 64. Each doubling
+//     halves the potential of the innermost point, bringing it closer to
+//     the set.
+//   - maxIterations: Maximum number of Newton steps per ray point
+//
+// Returns:
+//   - A flat array [real0, imag0, real1, imag1, ...] of 1 + 4*depth points
+//     from radius 65536 inward, or fewer if Newton's method diverged at the
+//     precision limit. {error} for invalid arguments.
+func traceExternalRay(this js.Value, args []js.Value) interface{} {
+	r := readArgs("traceExternalRay", args, 3)
+	angle := r.number(0, "angle")
+	depth := r.number(1, "depth")
+	maxIterations := r.maxIterations(2)
+	if r.failed() {
+		return r.errorResult()
+	}
+	r.check(!math.IsNaN(angle) && !math.IsInf(angle, 0), "angle must be finite, got %v", angle)
+	r.check(depth >= 1 && depth <= maxRayDepth, "depth must be from 1 to %d, got %v", maxRayDepth, depth)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// Tiny negative angles round up to 1 after reduction
+	angle -= math.Floor(angle)
+	if angle >= 1 {
+		angle = 0
+	}
+	points := externalRayPoints(angle, int(depth), maxIterations)
+
+	values := make([]interface{}, 0, 2*len(points))
+	for _, c := range points {
+		values = append(values, real(c), imag(c))
+	}
+	return js.ValueOf(values)
+}
@@ -0,0 +1,173 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Stateful progressive rendering
+//
+// A renderer created by createRenderer owns a viewport and an iteration
+// buffer and walks the pass sequence in progressiveStrides itself. Each
+// renderStep computes one pass with renderViewportPass's bookkeeping, skipping
+// the pixels earlier passes computed, and then copies every computed pixel
+// over the block to its lower right that the next pass will refine. The
+// buffer therefore always holds a complete, blocky preview that sharpens with
+// each step.
+
+// progressiveStrides are the pass strides of a stateful renderer, coarsest
+// first; each divides the one before it
+var progressiveStrides = []int{8, 4, 2, 1}
+
+// progressiveRenderer is the state behind a renderer handle
+type progressiveRenderer struct {
+	view                viewport
+	maxIterations       uint32
+	escapeRadiusSquared float64
+	results             []uint32
+	nextPass            int
+}
+
+// renderers maps the handles returned by createRenderer to their state
+var (
+	renderers          = map[int]*progressiveRenderer{}
+	nextRendererHandle = 1
+)
+
+// renderer returns the renderer whose handle is the argument at index
+func (r *argReader) renderer(index int) *progressiveRenderer {
+	handle := r.integer(index, "handle")
+	if r.failed() {
+		return nil
+	}
+	renderer, ok := renderers[handle]
+	r.check(ok, "handle %d is not a live renderer", handle)
+	return renderer
+}
+
+// step computes the next pass and fills the preview blocks around it
+//
+// Returns the number of pixels computed and whether the pass completed. A
+// cancelled pass is repeated by the next step.
+func (p *progressiveRenderer) step() (int, bool) {
+	stride := progressiveStrides[p.nextPass]
+	skipStride := 0
+	if p.nextPass > 0 {
+		skipStride = progressiveStrides[p.nextPass-1]
+	}
+
+	computed, ok := p.view.fillPass(p.results, stride, 0, 0, skipStride, p.maxIterations, p.escapeRadiusSquared)
+	if !ok {
+		return computed, false
+	}
+
+	if stride > 1 {
+		for y := 0; y < p.view.height; y++ {
+			for x := 0; x < p.view.width; x++ {
+				if x%stride != 0 || y%stride != 0 {
+					p.results[y*p.view.width+x] = p.results[(y-y%stride)*p.view.width+x-x%stride]
+				}
+			}
+		}
+	}
+	p.nextPass++
+	return computed, true
+}
+
+// createRenderer creates a progressive renderer for a viewport
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - A handle to pass to renderStep, getBuffer and destroyRenderer, or
+//     {error} for invalid arguments
+func createRenderer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("createRenderer", args, 7)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	handle := nextRendererHandle
+	nextRendererHandle++
+	renderers[handle] = &progressiveRenderer{
+		view:                view,
+		maxIterations:       maxIterations,
+		escapeRadiusSquared: escapeThreshold(escapeRadius),
+		results:             make([]uint32, view.pixelCount()),
+	}
+	return handle
+}
+
+// renderStep computes the next pass of a renderer, coarse to fine
+//
+// Parameters:
+//   - handle: Renderer returned by createRenderer
+//
+// Returns:
+//   - true while more passes remain after this one, false once the buffer
+//     holds the full-resolution render (also for calls after that, which do
+//     nothing), or {error} for an unknown handle. If cancelRender stops the
+//     pass, {written, cancelled: true} where written counts the pixels
+//     computed; the next call repeats the pass.
+func renderStep(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderStep", args, 1)
+	renderer := r.renderer(0)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	if renderer.nextPass == len(progressiveStrides) {
+		return false
+	}
+
+	beginRender()
+	computed, ok := renderer.step()
+	if !ok {
+		return cancelledResult(computed)
+	}
+	return renderer.nextPass < len(progressiveStrides)
+}
+
+// getBuffer returns a copy of a renderer's current iteration counts
+//
+// Parameters:
+//   - handle: Renderer returned by createRenderer
+//
+// Returns:
+//   - A Uint32Array of width*height iteration counts in row-major order, the
+//     preview of the passes completed so far (all zeros before the first),
+//     or {error} for an unknown handle
+func getBuffer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("getBuffer", args, 1)
+	renderer := r.renderer(0)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	return js.Global().Get("Uint32Array").New(newUint32ArrayBuffer(renderer.results))
+}
+
+// destroyRenderer releases a renderer and its buffer
+//
+// Parameters:
+//   - handle: Renderer returned by createRenderer; it is invalid afterwards
+//
+// Returns:
+//   - true once the renderer is released, or {error} for an unknown handle
+func destroyRenderer(this js.Value, args []js.Value) interface{} {
+	r := readArgs("destroyRenderer", args, 1)
+	r.renderer(0)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	delete(renderers, r.integer(0, "handle"))
+	return true
+}
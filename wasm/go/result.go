@@ -0,0 +1,55 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// structuredResults selects the return shape of the single-point functions,
+// changed from JavaScript via setStructuredResults. Plain iteration counts are
+// the default so existing callers are unaffected.
+var structuredResults = false
+
+// setStructuredResults switches the single-point functions (calculatePoint,
+// calculateJuliaPoint, calculateMultibrotPoint, calculateBurningShipPoint and
+// calculateTricornPoint) between returning a bare iteration count and an
+// object {escaped, iterations, smooth}
+//
+// Parameters:
+//   - enabled: When true, the single-point functions return objects
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setStructuredResults(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setStructuredResults", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	structuredResults = r.value(0).Truthy()
+	return true
+}
+
+// pointResult builds the structured result of a single point from the
+// iteration count and final |z|^2 returned by an escape-time loop
+//
+// A point has escaped when its final magnitude is outside the escape radius,
+// which also catches orbits that escape on exactly the last iteration and
+// would otherwise be indistinguishable from interior points. For points that
+// did not escape, smooth is maxIterations like the smooth calculatePoint.
+// power is the degree of the iterated polynomial, used by the smooth count.
+func pointResult(iterations uint32, zMagnitudeSquared, escapeRadiusSquared float64, power int) map[string]interface{} {
+	escaped := zMagnitudeSquared > escapeRadiusSquared
+
+	smooth := float64(iterations)
+	if escaped {
+		smooth = smoothIterationsForPower(iterations, zMagnitudeSquared, power)
+	}
+
+	return map[string]interface{}{
+		"escaped":    escaped,
+		"iterations": iterations,
+		"smooth":     smooth,
+	}
+}
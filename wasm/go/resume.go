@@ -0,0 +1,178 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Resumable rendering
+//
+// renderViewportState renders like renderViewport and also saves where each
+// pixel's orbit stopped, so continueRender can later carry on with more
+// iterations instead of starting over. Pixels that escaped are left alone,
+// and the rest pick up exactly where they stopped: after any number of
+// continuations the counts are those of one render with the total
+// maxIterations.
+//
+// The state holds stateStride float64s per pixel in row-major order:
+//
+//	cReal, cImag, zReal, zImag, iterations
+//
+// Resumed pixels are iterated with the plain float64 loop, so the state
+// ignores setHighPrecision, setFixedPoint, setPeriodicityCheck and
+// setBailoutShape.
+
+// stateStride is the number of float64s stored per pixel
+const stateStride = 5
+
+// resumeEscapeTime iterates z = z^2 + c from iteration start, where z is the
+// orbit value reached so far, until it escapes or reaches maxIterations
+//
+// Returns the iteration count and the orbit value it stopped at, which is
+// the escaping value for points that escaped.
+func resumeEscapeTime(zReal, zImag, cReal, cImag float64, start, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64, float64) {
+	for iteration := start; iteration < maxIterations; iteration++ {
+		if zReal*zReal+zImag*zImag > escapeRadiusSquared {
+			return iteration, zReal, zImag
+		}
+
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+	}
+	return maxIterations, zReal, zImag
+}
+
+// advanceState runs resumeEscapeTime on every pixel of state that hasn't
+// escaped, allowing each up to extra more iterations, and stores the counts
+// in results
+//
+// Pixels in the main cardioid or period-2 bulb are counted without
+// iterating, as the viewport renderers do. Returns the number of leading
+// pixels that were completed.
+func advanceState(state []float64, results []uint32, extra uint32, escapeRadiusSquared float64) int {
+	return parallelFor(len(results), func(start, end int) int {
+		for i := start; i < end; i++ {
+			if (i-start)%pointsPerCancelCheck == 0 && isRenderCancelled() {
+				return i - start
+			}
+
+			pixel := state[i*stateStride : (i+1)*stateStride]
+			cReal, cImag, zReal, zImag := pixel[0], pixel[1], pixel[2], pixel[3]
+			iterations := uint32(pixel[4])
+			limit := uint32(math.Min(float64(iterations)+float64(extra), math.MaxUint32))
+
+			switch {
+			case zReal*zReal+zImag*zImag > escapeRadiusSquared:
+				// Already escaped
+			case escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag):
+				iterations = limit
+			default:
+				iterations, pixel[2], pixel[3] = resumeEscapeTime(zReal, zImag, cReal, cImag, iterations, limit, escapeRadiusSquared)
+			}
+			pixel[4] = float64(iterations)
+			results[i] = iterations
+		}
+		return end - start
+	})
+}
+
+// renderViewportState renders a viewport like renderViewport and saves each
+// pixel's orbit state for continueRender
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - stateBuf: Float64Array of at least 5*width*height elements receiving
+//     cReal, cImag, zReal, zImag and the iteration count of every pixel
+//   - resultBuf: Uint32Array of at least width*height elements receiving the
+//     iteration counts in row-major order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffers
+//     are invalid. If cancelRender stops the render, {written, cancelled: true}
+//     where written counts the leading pixels filled in both buffers.
+func renderViewportState(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderViewportState", args, 9)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	stateBuf := r.typedArray(7, "stateBuf", "Float64Array")
+	resultBuf := r.typedArray(8, "resultBuf", "Uint32Array")
+	r.minLength(stateBuf, "stateBuf", stateStride*view.pixelCount())
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	// A fresh state has every orbit at z = 0 after 0 iterations
+	state := make([]float64, stateStride*view.pixelCount())
+	for y := 0; y < view.height; y++ {
+		for x := 0; x < view.width; x++ {
+			index := (y*view.width + x) * stateStride
+			state[index], state[index+1] = view.pointAt(x, y)
+		}
+	}
+
+	beginRender()
+	results := make([]uint32, view.pixelCount())
+	completed := advanceState(state, results, maxIterations, escapeThreshold(escapeRadius))
+
+	writeFloat64s(stateBuf, state[:completed*stateStride])
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
+
+// continueRender resumes a render saved by renderViewportState, iterating
+// the pixels that haven't escaped for more iterations
+//
+// Parameters:
+//   - stateBuf: Float64Array written by renderViewportState (or an earlier
+//     continueRender), updated in place; its length divided by 5 is the
+//     number of pixels
+//   - additionalIterations: Further iterations allowed for each pixel that
+//     hasn't escaped, from 1 to the limit set with setMaxIterationsLimit
+//   - escapeRadius: Threshold beyond which a point is considered escaped;
+//     pass the radius the state was rendered with
+//   - resultBuf: Uint32Array with an element per pixel receiving the updated
+//     iteration counts
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffers
+//     are invalid. If cancelRender stops the render, {written, cancelled: true}
+//     where written counts the leading pixels advanced in both buffers; the
+//     others are left as they were.
+func continueRender(this js.Value, args []js.Value) interface{} {
+	r := readArgs("continueRender", args, 4)
+	stateBuf := r.typedArray(0, "stateBuf", "Float64Array")
+	additionalIterations := r.iterationCount(1, "additionalIterations")
+	escapeRadius := r.escapeRadius(2)
+	resultBuf := r.typedArray(3, "resultBuf", "Uint32Array")
+	if r.failed() {
+		return r.errorResult()
+	}
+	pixels := stateBuf.Length() / stateStride
+	r.minLength(resultBuf, "resultBuf", pixels)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	state := readFloat64s(stateBuf)[:pixels*stateStride]
+
+	beginRender()
+	results := make([]uint32, pixels)
+	completed := advanceState(state, results, additionalIterations, escapeThreshold(escapeRadius))
+
+	writeFloat64s(stateBuf, state[:completed*stateStride])
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,95 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// Streaming by rows
+//
+// A worker that streams an image to the main thread renders it as a series
+// of horizontal bands, posting each one as soon as it is done, so the image
+// appears top to bottom instead of all at once. renderRows computes one band
+// of a full viewport, with every pixel sampled exactly as renderViewport
+// samples it, so the bands tile the full render without seams.
+
+// fillRows computes the iteration counts of rowCount rows of the viewport
+// starting at startRow, in row-major order, sampling at jittered positions
+// when setJitter is enabled
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillRows(results []uint32, startRow, rowCount int, maxIterations uint32, escapeRadiusSquared float64) int {
+	var offsets []sampleOffset
+	if sampleJitter {
+		offsets = jitterOffsets(rowCount * v.width)
+	}
+
+	completedRows := parallelFor(rowCount, func(first, last int) int {
+		for row := first; row < last; row++ {
+			if (row-first)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return row - first
+			}
+
+			for x := 0; x < v.width; x++ {
+				var offset sampleOffset
+				if offsets != nil {
+					offset = offsets[row*v.width+x]
+				}
+				results[row*v.width+x] = v.countAt(x, startRow+row, offset.dx, offset.dy, maxIterations, escapeRadiusSquared)
+			}
+		}
+		return last - first
+	})
+
+	return completedRows * v.width
+}
+
+// renderRows renders a horizontal band of a viewport, for streaming an image
+// row by row from a worker
+//
+// Parameters:
+//   - startRow: First row of the band, from 0
+//   - rowCount: Number of rows in the band, at least 1; the band must end at
+//     or before row height
+//   - width, height, centerReal, centerImag, scale: The full viewport, as
+//     for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least rowCount*width elements receiving
+//     the band's iteration counts in row-major order, starting at index 0
+//
+// Neighbor guessing is not applied, since a band has no neighbors above or
+// below it.
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderRows(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderRows", args, 10)
+	startRow := r.integer(0, "startRow")
+	rowCount := r.positiveInteger(1, "rowCount")
+	view := r.viewport(2)
+	maxIterations := r.maxIterations(7)
+	escapeRadius := r.escapeRadius(8)
+	resultBuf := r.typedArray(9, "resultBuf", "Uint32Array")
+	if r.failed() {
+		return r.errorResult()
+	}
+	r.check(startRow >= 0, "startRow must not be negative, got %d", startRow)
+	r.check(startRow+rowCount <= view.height, "rows %d to %d lie outside the %d rows of the viewport", startRow, startRow+rowCount-1, view.height)
+	r.minLength(resultBuf, "resultBuf", rowCount*view.width)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results := make([]uint32, rowCount*view.width)
+	completed := view.fillRows(results, startRow, rowCount, maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf, results[:completed])
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
@@ -0,0 +1,53 @@
+//go:build js
+
+package main
+
+import (
+	"syscall/js"
+)
+
+// maxScanlineWidth bounds computeScanline's width at 65536 pixels, beyond
+// the widest canvas browsers allow
+const maxScanlineWidth = 1 << 16
+
+// computeScanline computes the smooth iteration counts of one row of a
+// viewport, as a CPU reference for checking shader output row by row
+//
+// Every pixel is sampled exactly as renderViewport samples it, and the
+// values equal the matching row of renderSmoothFloat32 before the rounding
+// to float32.
+//
+// Parameters:
+//   - y: Row to compute, from 0 at the top to height - 1
+//   - width, height, centerReal, centerImag, scale: The full viewport, as
+//     for renderViewport; height places the row on the plane. width is at
+//     most 65536.
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - A Float64Array of width smooth counts from left to right. Points that
+//     don't escape receive maxIterations, as calculatePoint returns with
+//     smooth set. {error} for invalid arguments.
+func computeScanline(this js.Value, args []js.Value) interface{} {
+	r := readArgs("computeScanline", args, 8)
+	y := r.integer(0, "y")
+	view := r.viewport(1)
+	maxIterations := r.maxIterations(6)
+	escapeRadius := r.escapeRadius(7)
+	if r.failed() {
+		return r.errorResult()
+	}
+	r.check(y >= 0 && y < view.height, "y must be in [0, %d], got %d", view.height-1, y)
+	r.check(view.width <= maxScanlineWidth, "width must be at most %d, got %d", maxScanlineWidth, view.width)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	values := make([]float64, view.width)
+	for x := range values {
+		smooth, _ := view.smoothAt(x, y, 0, 0, maxIterations, escapeThreshold(escapeRadius))
+		values[x] = smooth
+	}
+	return newFloat64Array(values)
+}
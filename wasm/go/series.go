@@ -0,0 +1,142 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"syscall/js"
+)
+
+// Series approximation
+//
+// Near the reference orbit, a pixel's perturbation dz_n is a polynomial in
+// its offset dc from the reference point. Keeping the first three terms,
+//
+//	dz_n ~ A_n*dc + B_n*dc^2 + C_n*dc^3
+//
+// where substituting into dz_{n+1} = 2*Z_n*dz_n + dz_n^2 + dc gives
+//
+//	A_{n+1} = 2*Z_n*A_n + 1
+//	B_{n+1} = 2*Z_n*B_n + A_n^2
+//	C_{n+1} = 2*Z_n*C_n + 2*A_n*B_n
+//
+// starting from A_0 = B_0 = C_0 = 0. The coefficients depend only on the
+// reference orbit, so they are iterated once per render, and every pixel
+// starts its perturbation loop at the last iteration the series is still
+// accurate for, instead of at 0. At deep zooms most of the iterations of a
+// pixel are spent where the series holds, which is what makes such renders
+// fast.
+
+// seriesApproximation selects series approximation for renderPerturbation,
+// changed from JavaScript via setSeriesApproximation
+var seriesApproximation = false
+
+// seriesTolerance bounds the cubic term of the series relative to the
+// quadratic one at the largest offset in the view; once the cubic term grows
+// past this fraction, the neglected higher terms can no longer be ignored and
+// the series stops
+const seriesTolerance = 1e-6
+
+// seriesCoefficients are the coefficients of the series at iteration skip
+type seriesCoefficients struct {
+	skip    uint32
+	a, b, c complex128
+}
+
+// evaluate returns the series approximation of dz at iteration skip
+func (s seriesCoefficients) evaluate(dc complex128) complex128 {
+	return ((s.c*dc+s.b)*dc + s.a) * dc
+}
+
+// approximateSeries iterates the series coefficients along a reference orbit
+// for offsets up to maxOffset from the reference point, stopping at the
+// first iteration where the series loses accuracy or a pixel could escape
+//
+// The skip never reaches the last orbit value, so pixels always iterate at
+// least once and reference escapes are still detected as glitches.
+func approximateSeries(orbitReal, orbitImag []float64, maxOffset, escapeRadiusSquared float64) seriesCoefficients {
+	escapeRadius := math.Sqrt(escapeRadiusSquared)
+	var current seriesCoefficients
+
+	for n := 0; n+2 < len(orbitReal); n++ {
+		z := complex(orbitReal[n], orbitImag[n])
+		next := seriesCoefficients{
+			skip: current.skip + 1,
+			a:    2*z*current.a + 1,
+			b:    2*z*current.b + current.a*current.a,
+			c:    2*z*current.c + 2*current.a*current.b,
+		}
+
+		linear := cmplx.Abs(next.a) * maxOffset
+		quadratic := cmplx.Abs(next.b) * maxOffset * maxOffset
+		cubic := cmplx.Abs(next.c) * maxOffset * maxOffset * maxOffset
+		if cubic > seriesTolerance*quadratic {
+			break
+		}
+
+		// No pixel in the view may escape during the skipped iterations
+		reference := math.Hypot(orbitReal[n+1], orbitImag[n+1])
+		if reference+linear+quadratic+cubic > escapeRadius {
+			break
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// setSeriesApproximation enables or disables series approximation for
+// renderPerturbation
+//
+// Parameters:
+//   - enabled: When true, pixels skip the leading iterations the series
+//     approximation covers
+//
+// Returns:
+//   - true when the setting was applied, {error} for invalid arguments
+func setSeriesApproximation(this js.Value, args []js.Value) interface{} {
+	r := readArgs("setSeriesApproximation", args, 1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	seriesApproximation = r.value(0).Truthy()
+	return true
+}
+
+// calculateSeriesSkip computes the series approximation of the reference
+// orbit renderPerturbation uses for a viewport
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderPerturbation
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The number of iterations every pixel of the viewport can skip, or
+//     {error} for invalid arguments
+func calculateSeriesSkip(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateSeriesSkip", args, 7)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	orbitReal, orbitImag := referenceOrbit(
+		doubleDouble{hi: view.centerReal},
+		doubleDouble{hi: view.centerImag},
+		maxIterations,
+		escapeThreshold(escapeRadius),
+	)
+	return approximateSeries(orbitReal, orbitImag, view.maxOffset(), escapeThreshold(escapeRadius)).skip
+}
+
+// maxOffset returns the distance from the viewport center to its farthest
+// corner in complex-plane units
+func (v viewport) maxOffset() float64 {
+	return math.Hypot(float64(v.width)/2*v.scaleX, float64(v.height)/2*v.scaleY)
+}
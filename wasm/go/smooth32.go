@@ -0,0 +1,133 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// renderSmoothFloat32 renders the smooth iteration count of every pixel of a
+// viewport into a Float32Array, for upload as a float texture
+//
+// Counts are computed in float64 and rounded to float32 once, in Go, while
+// they are packed for the copy, so the transfer is half the size of float64
+// values and JS needs no conversion pass.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Float32Array of at least width*height elements receiving the
+//     smooth counts in row-major order. Points that don't escape receive
+//     maxIterations, as calculatePoint returns with smooth set.
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderSmoothFloat32(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderSmoothFloat32", args, 8)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	resultBuf := r.typedArray(7, "resultBuf", "Float32Array")
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	values := make([]float64, view.pixelCount())
+	completed := view.fillSmooth(values, maxIterations, escapeThreshold(escapeRadius))
+	values = values[:completed]
+	for i, smooth := range values {
+		if smooth == interiorSmooth {
+			values[i] = float64(maxIterations)
+		}
+	}
+
+	writeFloat32s(resultBuf, values)
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
+
+// textureChannels is the number of float32 values renderFloatTexture writes
+// per pixel
+const textureChannels = 4
+
+// renderFloatTexture renders every pixel of a viewport as four float32
+// channels for a fragment shader, so all coloring can happen on the GPU
+//
+// Per pixel, in RGBA order:
+//   - R: the smooth iteration count divided by maxIterations, clamped to
+//     [0, 1]; 1 for interior points
+//   - G: the angle atan2(zImag, zReal) of the first orbit value outside the
+//     escape radius, in [-pi, pi]; 0 for interior points
+//   - B: the magnitude |z| of that value; 0 for interior points
+//   - A: 1 for interior points, 0 for points that escaped
+//
+// Orbits use the plain float64 loop, so the precision modes, periodicity
+// checking and the square bailout don't apply; points in the main cardioid
+// or period-2 bulb are counted as interior without iterating.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - floatBuf: Float32Array of at least 4*width*height elements receiving
+//     the channels in row-major pixel order
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     A cancelled render returns {written, cancelled: true} like renderViewport.
+func renderFloatTexture(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderFloatTexture", args, 8)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	floatBuf := r.typedArray(7, "floatBuf", "Float32Array")
+	r.minLength(floatBuf, "floatBuf", textureChannels*view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+	values := make([]float64, textureChannels*view.pixelCount())
+	completedRows := parallelFor(view.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < view.width; x++ {
+				texel := values[(y*view.width+x)*textureChannels:][:textureChannels]
+				cReal, cImag := view.pointAt(x, y)
+				if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag) {
+					texel[0], texel[3] = 1, 1
+					continue
+				}
+
+				iterations, zReal, zImag := resumeEscapeTime(0, 0, cReal, cImag, 0, maxIterations, escapeRadiusSquared)
+				if iterations >= maxIterations {
+					texel[0], texel[3] = 1, 1
+					continue
+				}
+				zMagnitudeSquared := zReal*zReal + zImag*zImag
+				texel[0] = math.Min(math.Max(smoothIterations(iterations, zMagnitudeSquared)/float64(maxIterations), 0), 1)
+				texel[1] = math.Atan2(zImag, zReal)
+				texel[2] = math.Sqrt(zMagnitudeSquared)
+			}
+		}
+		return endRow - startRow
+	})
+
+	completed := completedRows * view.width
+	writeFloat32s(floatBuf, values[:textureChannels*completed])
+	if completed < view.pixelCount() {
+		return cancelledResult(completed)
+	}
+	return completed
+}
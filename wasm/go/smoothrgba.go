@@ -0,0 +1,102 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Log-scaled smooth coloring
+//
+// The smooth iteration count is mapped to a palette index on a logarithmic
+// scale, log(1 + smooth) / log(1 + maxIterations), so the many bands close to
+// the set get as much of the palette as the few wide bands far from it. The
+// index falls between two of the caller's palette colors, which are blended
+// linearly by its fractional part, so there is no visible banding anywhere.
+
+// logPaletteIndex returns the position of a smooth iteration count in a
+// palette of colorCount colors, in [0, colorCount-1]
+func logPaletteIndex(smooth float64, maxIterations uint32, colorCount int) float64 {
+	t := math.Log1p(math.Max(0, smooth)) / math.Log1p(float64(maxIterations))
+	return math.Max(0, math.Min(1, t)) * float64(colorCount-1)
+}
+
+// interpolateColors blends the two palette colors on either side of index
+func interpolateColors(colors []rgb, index float64) rgb {
+	low := int(math.Floor(index))
+	if low >= len(colors)-1 {
+		return colors[len(colors)-1]
+	}
+
+	fraction := index - float64(low)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + fraction*(float64(b)-float64(a))))
+	}
+	from, to := colors[low], colors[low+1]
+	return rgb{mix(from.r, to.r), mix(from.g, to.g), mix(from.b, to.b)}
+}
+
+// readPaletteColors converts a buffer of RGB triples to colors
+func readPaletteColors(paletteBuf js.Value) []rgb {
+	bytes := make([]byte, paletteBuf.Length())
+	js.CopyBytesToGo(bytes, paletteBuf)
+
+	colors := make([]rgb, len(bytes)/3)
+	for i := range colors {
+		colors[i] = rgb{bytes[i*3], bytes[i*3+1], bytes[i*3+2]}
+	}
+	return colors
+}
+
+// renderRGBASmooth renders a viewport of the Mandelbrot set straight to RGBA
+// pixels, coloring escaped points by their smooth iteration count on a
+// logarithmic palette scale with linear interpolation between palette colors
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - rgbaBuf: Uint8ClampedArray (or Uint8Array) of at least 4*width*height
+//     bytes, e.g. ImageData.data, receiving RGBA pixels in row-major order
+//   - paletteBuf: Uint8Array (or Uint8ClampedArray) of RGB triples, at least
+//     two colors; the first is used for the fastest-escaping points and the
+//     last for points escaping at maxIterations
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffers are
+//     invalid. Interior points are black. A cancelled render returns
+//     {written, cancelled: true} like renderViewport.
+func renderRGBASmooth(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderRGBASmooth", args, 9)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	rgbaBuf := r.byteArray(7, "rgbaBuf")
+	r.minLength(rgbaBuf, "rgbaBuf", view.pixelCount()*4)
+	paletteBuf := r.byteArray(8, "paletteBuf")
+	r.minLength(paletteBuf, "paletteBuf", 6)
+	if !r.failed() {
+		r.check(paletteBuf.Length()%3 == 0, "paletteBuf must hold RGB triples, got %d bytes", paletteBuf.Length())
+	}
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	colors := readPaletteColors(paletteBuf)
+
+	beginRender()
+	values := make([]float64, view.pixelCount())
+	completed := view.fillSmooth(values, maxIterations, escapeThreshold(escapeRadius))
+
+	pixels := make([]byte, completed*4)
+	colorPixels(pixels, values[:completed], func(smooth float64) rgb {
+		return interpolateColors(colors, logPaletteIndex(smooth, maxIterations, len(colors)))
+	})
+
+	js.CopyBytesToJS(rgbaBuf, pixels)
+	if completed < len(values) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
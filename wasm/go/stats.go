@@ -0,0 +1,163 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"sync"
+	"syscall/js"
+)
+
+// regionStats samples a square region on a coarse grid and summarizes its
+// escape counts, so an app can choose maxIterations before a full render
+//
+// The grid has k x k points at pixel centers, where k*k is the largest
+// square not above sampleCount. Samples go through the viewport iteration
+// path, so fixed-point and high-precision modes apply, but neighbor guessing
+// does not: the samples are too far apart for it to be sound.
+//
+// Parameters:
+//   - centerReal: Real component at the center of the region
+//   - centerImag: Imaginary component at the center of the region
+//   - scale: Width and height of the region in complex-plane units
+//   - sampleCount: Number of samples, from 1 to 268435456 (16384 x 16384)
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - An object {mean, max, interiorFraction}: the mean and largest
+//     iteration counts of the samples that escaped (both 0 when none did),
+//     and the fraction of samples that reached maxIterations. {error} for
+//     invalid arguments.
+func regionStats(this js.Value, args []js.Value) interface{} {
+	r := readArgs("regionStats", args, 6)
+	centerReal := r.number(0, "centerReal")
+	centerImag := r.number(1, "centerImag")
+	scale := r.number(2, "scale")
+	sampleCount := r.number(3, "sampleCount")
+	maxIterations := r.maxIterations(4)
+	escapeRadius := r.escapeRadius(5)
+	r.check(scale > 0, "scale must be greater than 0, got %v", scale)
+	r.check(sampleCount >= 1 && sampleCount <= maxViewportPixels, "sampleCount must be from 1 to %d, got %v", maxViewportPixels, sampleCount)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	side := int(math.Sqrt(sampleCount))
+	for float64((side+1)*(side+1)) <= sampleCount {
+		side++
+	}
+	view := viewport{width: side, height: side, centerReal: centerReal, centerImag: centerImag, scaleX: scale / float64(side), scaleY: scale / float64(side)}
+
+	// Each worker accumulates its rows, then merges into the totals
+	total, largest, interior := uint64(0), uint32(0), 0
+	var mu sync.Mutex
+	parallelFor(view.height, func(startRow, endRow int) int {
+		localTotal, localLargest, localInterior := uint64(0), uint32(0), 0
+		for y := startRow; y < endRow; y++ {
+			for x := 0; x < view.width; x++ {
+				iterations, _ := view.escapeTimeAt(x, y, 0, 0, maxIterations, escapeThreshold(escapeRadius))
+				if iterations == maxIterations {
+					localInterior++
+					continue
+				}
+				localTotal += uint64(iterations)
+				localLargest = max(localLargest, iterations)
+			}
+		}
+
+		mu.Lock()
+		total += localTotal
+		largest = max(largest, localLargest)
+		interior += localInterior
+		mu.Unlock()
+		return endRow - startRow
+	})
+
+	mean := 0.0
+	if escaped := view.pixelCount() - interior; escaped > 0 {
+		mean = float64(total) / float64(escaped)
+	}
+	return map[string]interface{}{
+		"mean":             mean,
+		"max":              largest,
+		"interiorFraction": float64(interior) / float64(view.pixelCount()),
+	}
+}
+
+// The box estimateArea samples, the upper half of a rectangle enclosing the
+// set, which is symmetric about the real axis
+const (
+	areaBoxMinReal = -2.0
+	areaBoxMaxReal = 0.5
+	areaBoxMaxImag = 1.15
+)
+
+// areaBlockSize is the number of points estimateArea draws at a time, so
+// memory use doesn't grow with the sample count
+const areaBlockSize = 1 << 16
+
+// estimateArea estimates the area of the Mandelbrot set by Monte Carlo
+// sampling
+//
+// Points are drawn uniformly from [-2, 0.5] x [0, 1.15] with sampleRand,
+// so setSeed makes the estimate repeatable, and counted as members when
+// they are in the main cardioid or period-2 bulb or don't escape radius 2
+// within maxIterations. The member fraction times the box area is doubled
+// for the lower half. Points that escape only after maxIterations count as
+// members, so the estimate shrinks towards the true area (about 1.5066) as
+// maxIterations grows.
+//
+// Parameters:
+//   - samples: Number of points to sample, at least 1
+//   - maxIterations: Maximum number of iterations per point
+//
+// Returns:
+//   - An object {area, standardError} where standardError is the standard
+//     error of the binomial estimate, or {error} for invalid arguments
+func estimateArea(this js.Value, args []js.Value) interface{} {
+	r := readArgs("estimateArea", args, 2)
+	samples := r.positiveInteger(0, "samples")
+	maxIterations := r.maxIterations(1)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	members := 0
+	points := make([]complex128, min(samples, areaBlockSize))
+	inside := make([]bool, len(points))
+	for drawn := 0; drawn < samples; drawn += len(points) {
+		points = points[:min(samples-drawn, len(points))]
+		for i := range points {
+			points[i] = complex(
+				areaBoxMinReal+sampleRand.Float64()*(areaBoxMaxReal-areaBoxMinReal),
+				sampleRand.Float64()*areaBoxMaxImag,
+			)
+		}
+
+		parallelFor(len(points), func(start, end int) int {
+			for i := start; i < end; i++ {
+				cReal, cImag := real(points[i]), imag(points[i])
+				inside[i] = inCardioidOrBulb(cReal, cImag)
+				if !inside[i] {
+					iterations, _ := escapeTime(0, 0, cReal, cImag, maxIterations, 4.0)
+					inside[i] = iterations == maxIterations
+				}
+			}
+			return end - start
+		})
+		for _, member := range inside[:len(points)] {
+			if member {
+				members++
+			}
+		}
+	}
+
+	// The box and its mirror image below the real axis
+	boxArea := 2 * (areaBoxMaxReal - areaBoxMinReal) * areaBoxMaxImag
+	fraction := float64(members) / float64(samples)
+	return map[string]interface{}{
+		"area":          boxArea * fraction,
+		"standardError": boxArea * math.Sqrt(fraction*(1-fraction)/float64(samples)),
+	}
+}
@@ -0,0 +1,191 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"sync"
+)
+
+// Supersampling anti-aliasing for the RGBA renderer
+//
+// Each output pixel is sampled at several subpixel offsets, every sample is
+// colored independently, and the colors are averaged. Averaging happens in
+// linear light: sRGB channel values are decoded to linear intensity, averaged
+// and encoded back, so edges don't come out darker than they should.
+
+// sampleOffset is a subpixel sample position relative to the pixel's own
+// sample point, in pixels
+type sampleOffset struct {
+	dx, dy float64
+}
+
+// gridOffsets returns an n x n regular grid of sample offsets centered on the
+// pixel's sample point, spaced 1/n pixel (scale/n complex units) apart. n = 1
+// gives the single offset (0, 0), reproducing unsampled rendering.
+func gridOffsets(n int) []sampleOffset {
+	offsets := make([]sampleOffset, 0, n*n)
+	for sy := 0; sy < n; sy++ {
+		for sx := 0; sx < n; sx++ {
+			offsets = append(offsets, sampleOffset{
+				dx: (float64(sx)+0.5)/float64(n) - 0.5,
+				dy: (float64(sy)+0.5)/float64(n) - 0.5,
+			})
+		}
+	}
+	return offsets
+}
+
+// maxSamplesPerAxis is the largest supersampling factor renderRGBA accepts,
+// 256 samples per pixel; larger grids would only multiply the render time
+// and could exhaust memory for their offsets
+const maxSamplesPerAxis = 16
+
+// Sample patterns accepted by renderRGBA
+const (
+	gridPattern        = "grid"
+	rotatedGridPattern = "rotated"
+)
+
+// rotatedGridOffsets returns the four sample offsets of 2x2 rotated-grid
+// supersampling (RGSS)
+//
+// The samples sit at 1/8, 3/8, 5/8 and 7/8 of the pixel along both axes, with
+// no two sharing a row or column. A near-horizontal or near-vertical edge
+// therefore crosses them one at a time, giving five coverage levels where the
+// regular 2x2 grid, whose samples share rows and columns, gives three.
+func rotatedGridOffsets() []sampleOffset {
+	return []sampleOffset{
+		{dx: 0.125 - 0.5, dy: 0.375 - 0.5},
+		{dx: 0.375 - 0.5, dy: 0.875 - 0.5},
+		{dx: 0.625 - 0.5, dy: 0.125 - 0.5},
+		{dx: 0.875 - 0.5, dy: 0.625 - 0.5},
+	}
+}
+
+// srgbToLinearTable decodes each 8-bit sRGB channel value to linear intensity
+var srgbToLinearTable = func() [256]float64 {
+	var table [256]float64
+	for i := range table {
+		c := float64(i) / 255.0
+		if c <= 0.04045 {
+			table[i] = c / 12.92
+		} else {
+			table[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return table
+}()
+
+// linearToSRGB encodes a linear intensity in [0, 1] as an 8-bit sRGB channel
+func linearToSRGB(linear float64) uint8 {
+	var c float64
+	if linear <= 0.0031308 {
+		c = linear * 12.92
+	} else {
+		c = 1.055*math.Pow(linear, 1.0/2.4) - 0.055
+	}
+	return channel(c)
+}
+
+// sampleHistogram counts escaped samples by iteration over every pixel and
+// offset, for histogram equalization of supersampled renders
+//
+// Returns false if the render was cancelled before all rows were sampled.
+func (v viewport) sampleHistogram(offsets []sampleOffset, maxIterations uint32, escapeRadiusSquared float64) ([]uint64, bool) {
+	counts := make([]uint64, maxIterations)
+	var mu sync.Mutex
+
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		local := make([]uint64, maxIterations)
+		defer func() {
+			mu.Lock()
+			for n, count := range local {
+				counts[n] += count
+			}
+			mu.Unlock()
+		}()
+
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				for _, offset := range offsets {
+					smooth, interior := v.smoothAt(x, y, offset.dx, offset.dy, maxIterations, escapeRadiusSquared)
+					if !interior {
+						local[histogramBin(smooth, len(local))]++
+					}
+				}
+			}
+		}
+		return endRow - startRow
+	})
+
+	return counts, completedRows == v.height
+}
+
+// fillRGBASupersampled is fillRGBA with every pixel averaged over the given
+// sample offsets
+//
+// Histogram equalization needs the distribution of all samples before any
+// pixel can be colored, so in that mode the samples are iterated twice: once
+// to build the histogram and once to color.
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillRGBASupersampled(pixels []byte, offsets []sampleOffset, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	colorFn := func(smooth float64) rgb {
+		return smoothColor(smooth, maxIterations)
+	}
+	if coloringMode == histogramColoring {
+		counts, ok := v.sampleHistogram(offsets, maxIterations, escapeRadiusSquared)
+		if !ok {
+			return 0
+		}
+
+		cdf := histogramCDF(counts)
+		colorFn = func(smooth float64) rgb {
+			return currentPalette(equalize(cdf, smooth))
+		}
+	}
+
+	sampleCount := float64(len(offsets))
+
+	completedRows := parallelFor(v.height, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				var r, g, b float64
+				for _, offset := range offsets {
+					smooth, interior := v.smoothAt(x, y, offset.dx, offset.dy, maxIterations, escapeRadiusSquared)
+
+					color := interiorColor
+					if !interior {
+						color = colorFn(smooth)
+					}
+
+					r += srgbToLinearTable[color.r]
+					g += srgbToLinearTable[color.g]
+					b += srgbToLinearTable[color.b]
+				}
+
+				i := (y*v.width + x) * 4
+				pixels[i] = linearToSRGB(r / sampleCount)
+				pixels[i+1] = linearToSRGB(g / sampleCount)
+				pixels[i+2] = linearToSRGB(b / sampleCount)
+				pixels[i+3] = 255
+			}
+		}
+		return endRow - startRow
+	})
+
+	return completedRows * v.width
+}
@@ -0,0 +1,81 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRotatedGridSmoothsNearHorizontalEdges renders the top of the disk
+// |c| <= 2, whose edge is nearly horizontal there, and checks that rotated
+// grid sampling estimates pixel coverage more closely and in more distinct
+// steps than the regular 2x2 grid
+func TestRotatedGridSmoothsNearHorizontalEdges(t *testing.T) {
+	defer func(saved palette) { currentPalette = saved }(currentPalette)
+	defer func(saved string) { coloringMode = saved }(coloringMode)
+
+	// With maxIterations 2, exactly the points outside the escape radius
+	// escape, so painting them white makes each pixel's linear intensity the
+	// fraction of its samples outside the disk
+	currentPalette = func(float64) rgb { return rgb{255, 255, 255} }
+	coloringMode = linearColoring
+	const maxIterations = 2
+	const escapeRadius = 2.0
+
+	view := viewport{width: 32, height: 8, centerReal: 0, centerImag: 2, scaleX: 1.0 / 32, scaleY: 1.0 / 32}
+
+	// exactCoverage estimates the fraction of pixel (x, y) outside the disk
+	// on a fine grid
+	exactCoverage := func(x, y int) float64 {
+		const n = 64
+		outside := 0
+		for _, offset := range gridOffsets(n) {
+			cReal, cImag := view.pointAtOffset(x, y, offset.dx, offset.dy)
+			if cReal*cReal+cImag*cImag > escapeRadius*escapeRadius {
+				outside++
+			}
+		}
+		return float64(outside) / (n * n)
+	}
+
+	// edgeMetrics returns the mean coverage error over the pixels the edge
+	// crosses and the number of distinct intensities among them
+	edgeMetrics := func(offsets []sampleOffset) (float64, int) {
+		pixels := make([]byte, view.pixelCount()*4)
+		if completed := view.fillRGBASupersampled(pixels, offsets, maxIterations, escapeRadius*escapeRadius); completed != view.pixelCount() {
+			t.Fatalf("rendered %d of %d pixels", completed, view.pixelCount())
+		}
+
+		totalError := 0.0
+		edgePixels := 0
+		levels := map[byte]bool{}
+		for y := 0; y < view.height; y++ {
+			for x := 0; x < view.width; x++ {
+				exact := exactCoverage(x, y)
+				if exact == 0 || exact == 1 {
+					continue
+				}
+
+				level := pixels[(y*view.width+x)*4]
+				totalError += math.Abs(srgbToLinearTable[level] - exact)
+				edgePixels++
+				levels[level] = true
+			}
+		}
+		if edgePixels == 0 {
+			t.Fatal("no pixels straddle the edge")
+		}
+		return totalError / float64(edgePixels), len(levels)
+	}
+
+	gridError, gridLevels := edgeMetrics(gridOffsets(2))
+	rotatedError, rotatedLevels := edgeMetrics(rotatedGridOffsets())
+
+	if rotatedError >= gridError {
+		t.Errorf("rotated grid coverage error %.4f, want below the regular grid's %.4f", rotatedError, gridError)
+	}
+	if rotatedLevels <= gridLevels {
+		t.Errorf("rotated grid gave %d distinct edge intensities, want more than the regular grid's %d", rotatedLevels, gridLevels)
+	}
+}
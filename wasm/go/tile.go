@@ -0,0 +1,128 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Tile addressing
+//
+// Tiles follow the slippy map scheme: at zoom level z the base square is
+// split into 2^z x 2^z tiles, numbered from (0, 0) at the top-left with tileX
+// increasing to the right and tileY increasing downward. Zoom 0 is a single
+// tile covering real [-2.5, 1] and imag [-1.75, 1.75], a square around the
+// whole set, so every tile has square pixels.
+
+const (
+	// tileBaseReal is the real coordinate of the left edge of the base square
+	tileBaseReal = -2.5
+	// tileBaseImag is the imaginary coordinate of the top edge of the base square
+	tileBaseImag = 1.75
+	// tileBaseSize is the side length of the base square in complex units
+	tileBaseSize = 3.5
+	// maxTileZoom is the deepest zoom level, beyond which tile indices no
+	// longer convert exactly to float64
+	maxTileZoom = 52
+)
+
+// tileViewport returns the viewport of tile (tileX, tileY) at zoom, with
+// tileSize pixels per side
+//
+// Pixel (0, 0) samples the tile's top-left corner and pixel spacing is the
+// tile side divided by tileSize, so the pixels of adjacent tiles continue one
+// grid and tiles compose without seams or overlap.
+func tileViewport(tileX, tileY, zoom, tileSize int) viewport {
+	side := tileBaseSize / math.Ldexp(1, zoom)
+	return viewport{
+		width:      tileSize,
+		height:     tileSize,
+		centerReal: tileBaseReal + (float64(tileX)+0.5)*side,
+		centerImag: tileBaseImag - (float64(tileY)+0.5)*side,
+		scaleX:     side / float64(tileSize),
+		scaleY:     side / float64(tileSize),
+	}
+}
+
+// renderTile renders one square map tile addressed by its tile coordinates,
+// like renderViewport over the tile's region
+//
+// Parameters:
+//   - tileX: Tile column, from 0 to 2^zoom - 1
+//   - tileY: Tile row, from 0 to 2^zoom - 1
+//   - zoom: Zoom level, from 0 to 52
+//   - tileSize: Tile width and height in pixels
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Uint32Array of at least tileSize*tileSize elements receiving
+//     the iteration counts in row-major order
+//   - withStats (optional): When true, also report the fraction of pixels
+//     whose count is maxIterations
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     If cancelRender stops the render, an object {written, cancelled: true}
+//     where written counts the leading pixels that were filled. With
+//     withStats set, an object {written, maxIterationsFraction} instead, which
+//     also carries cancelled: true for a cancelled render; the fraction is
+//     over the pixels written.
+func renderTile(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderTile", args, 7, 8)
+	tileX := r.integer(0, "tileX")
+	tileY := r.integer(1, "tileY")
+	zoom := r.integer(2, "zoom")
+	tileSize, _ := r.dimensions(3, 3, "tileSize", "tileSize")
+	maxIterations := r.maxIterations(4)
+	escapeRadius := r.escapeRadius(5)
+	resultBuf := r.typedArray(6, "resultBuf", "Uint32Array")
+	r.check(zoom >= 0 && zoom <= maxTileZoom, "zoom must be between 0 and %d, got %d", maxTileZoom, zoom)
+	if !r.failed() {
+		tiles := 1 << zoom
+		r.check(tileX >= 0 && tileX < tiles, "tileX must be between 0 and %d at zoom %d, got %d", tiles-1, zoom, tileX)
+		r.check(tileY >= 0 && tileY < tiles, "tileY must be between 0 and %d at zoom %d, got %d", tiles-1, zoom, tileY)
+	}
+	r.minLength(resultBuf, "resultBuf", tileSize*tileSize)
+	withStats := r.flag(7)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	view := tileViewport(tileX, tileY, zoom, tileSize)
+	results, completed := view.escapeTimes(false, maxIterations, escapeThreshold(escapeRadius))
+
+	writeUint32s(resultBuf, results[:completed])
+	if withStats {
+		stats := map[string]interface{}{
+			"written":               completed,
+			"maxIterationsFraction": maxIterationsFraction(results[:completed], maxIterations),
+		}
+		if completed < len(results) {
+			stats["cancelled"] = true
+		}
+		return stats
+	}
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
+
+// maxIterationsFraction returns the fraction of results equal to
+// maxIterations, or 0 for no results
+//
+// A high fraction means many pixels ran out of iterations, and the tile may
+// show more detail when rendered with a larger maxIterations.
+func maxIterationsFraction(results []uint32, maxIterations uint32) float64 {
+	if len(results) == 0 {
+		return 0
+	}
+	maxed := 0
+	for _, count := range results {
+		if count == maxIterations {
+			maxed++
+		}
+	}
+	return float64(maxed) / float64(len(results))
+}
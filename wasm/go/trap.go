@@ -0,0 +1,138 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Orbit trap coloring
+//
+// An orbit trap records how close an orbit comes to a geometric shape. The
+// minimum distance over the orbit, rather than the escape iteration, then
+// drives the color. The starting value z_0 = 0 is the same for every point and
+// is not counted, so only z_1 onward is measured.
+
+// calculateOrbitTrapPoint calculates the minimum distance between a point's
+// Mandelbrot orbit and a trap point
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - trapReal: Real component of the trap point
+//   - trapImag: Imaginary component of the trap point
+//
+// Returns:
+//   - An object {iterations, distance}: the escape iteration as for
+//     calculatePoint, and the smallest |z_n - trap| over the orbit
+func calculateOrbitTrapPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateOrbitTrapPoint", args, 6)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	trapReal := r.number(4, "trapReal")
+	trapImag := r.number(5, "trapImag")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations, minDistanceSquared := orbitTrap(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) float64 {
+		dReal := zReal - trapReal
+		dImag := zImag - trapImag
+		return dReal*dReal + dImag*dImag
+	})
+
+	return map[string]interface{}{
+		"iterations": iterations,
+		"distance":   math.Sqrt(minDistanceSquared),
+	}
+}
+
+// calculateOrbitTrapLine calculates the minimum distance between a point's
+// Mandelbrot orbit and the line a*x + b*y + c = 0, where x and y are the real
+// and imaginary components
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - a, b, c: Coefficients of the line; a and b must not both be 0
+//
+// Returns:
+//   - An object {iterations, distance}: the escape iteration as for
+//     calculatePoint, and the smallest |a*x_n + b*y_n + c| / sqrt(a^2 + b^2)
+//     over the orbit
+func calculateOrbitTrapLine(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateOrbitTrapLine", args, 7)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	a := r.number(4, "a")
+	b := r.number(5, "b")
+	c := r.number(6, "c")
+	r.check(a != 0 || b != 0, "a and b must not both be 0")
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations, minDistance := orbitTrap(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) float64 {
+		return math.Abs(a*zReal + b*zImag + c)
+	})
+
+	return map[string]interface{}{
+		"iterations": iterations,
+		"distance":   minDistance / math.Hypot(a, b),
+	}
+}
+
+// calculateOrbitTrapCross calculates the minimum distance between a point's
+// Mandelbrot orbit and the cross formed by the real and imaginary axes
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - An object {iterations, distance}: the escape iteration as for
+//     calculatePoint, and the smallest min(|x_n|, |y_n|) over the orbit
+func calculateOrbitTrapCross(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateOrbitTrapCross", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	iterations, minDistance := orbitTrap(real, imag, maxIterations, escapeThreshold(escapeRadius), func(zReal, zImag float64) float64 {
+		return math.Min(math.Abs(zReal), math.Abs(zImag))
+	})
+
+	return map[string]interface{}{
+		"iterations": iterations,
+		"distance":   minDistance,
+	}
+}
+
+// orbitTrap walks a point's orbit and returns its escape iteration and the
+// smallest value of distance over z_1 onward, or +Inf for an empty orbit
+//
+// distance may return any measure that increases with the true distance,
+// such as its square, for the caller to convert once.
+func orbitTrap(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64, distance func(zReal, zImag float64) float64) (uint32, float64) {
+	minDistance := math.Inf(1)
+	iterations, _ := walkOrbit(cReal, cImag, maxIterations, escapeRadiusSquared, func(zReal, zImag float64) bool {
+		minDistance = math.Min(minDistance, distance(zReal, zImag))
+		return true
+	})
+	return iterations, minDistance
+}
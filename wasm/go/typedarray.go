@@ -0,0 +1,182 @@
+//go:build js
+
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"syscall/js"
+)
+
+// Helpers for moving bulk data across the JS boundary in a single copy.
+//
+// js.CopyBytesToGo and js.CopyBytesToJS only operate on Uint8Array, so typed
+// arrays are viewed as bytes and decoded on the Go side. WebAssembly memory is
+// little-endian, matching the byte order of JS typed arrays on every platform
+// browsers run on.
+
+// bytesOf returns a Uint8Array view over the same memory as a JS typed array
+func bytesOf(typedArray js.Value) js.Value {
+	return js.Global().Get("Uint8Array").New(
+		typedArray.Get("buffer"),
+		typedArray.Get("byteOffset"),
+		typedArray.Get("byteLength"),
+	)
+}
+
+// isTypedArray reports whether value is an instance of the named JS typed array constructor
+func isTypedArray(value js.Value, constructor string) bool {
+	return value.Type() == js.TypeObject && value.InstanceOf(js.Global().Get(constructor))
+}
+
+// isArrayBufferView reports whether value is any JS typed array or DataView
+func isArrayBufferView(value js.Value) bool {
+	return value.Type() == js.TypeObject && js.Global().Get("ArrayBuffer").Call("isView", value).Bool()
+}
+
+// isByteArray reports whether value is a Uint8Array or Uint8ClampedArray, the
+// two types js.CopyBytesToJS accepts
+func isByteArray(value js.Value) bool {
+	return isTypedArray(value, "Uint8Array") || isTypedArray(value, "Uint8ClampedArray")
+}
+
+// readFloat64s copies a JS array of numbers into a Go slice
+//
+// Float64Array inputs are copied in one CopyBytesToGo call; any other
+// array-like value falls back to reading one element at a time.
+func readFloat64s(array js.Value) []float64 {
+	if !isTypedArray(array, "Float64Array") {
+		values := make([]float64, array.Length())
+		for i := range values {
+			values[i] = array.Index(i).Float()
+		}
+		return values
+	}
+
+	raw := make([]byte, array.Get("byteLength").Int())
+	js.CopyBytesToGo(raw, bytesOf(array))
+
+	values := make([]float64, len(raw)/8)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:]))
+	}
+	return values
+}
+
+// readUint32s copies a JS array of non-negative integers into a Go slice
+//
+// Uint32Array inputs are copied in one CopyBytesToGo call; any other
+// array-like value falls back to reading one element at a time.
+func readUint32s(array js.Value) []uint32 {
+	if !isTypedArray(array, "Uint32Array") {
+		values := make([]uint32, array.Length())
+		for i := range values {
+			values[i] = uint32(array.Index(i).Int())
+		}
+		return values
+	}
+
+	raw := make([]byte, array.Get("byteLength").Int())
+	js.CopyBytesToGo(raw, bytesOf(array))
+
+	values := make([]uint32, len(raw)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return values
+}
+
+// newFloat64Array returns a new JS Float64Array holding values
+func newFloat64Array(values []float64) js.Value {
+	raw := make([]byte, len(values)*8)
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(raw[i*8:], math.Float64bits(value))
+	}
+
+	array := js.Global().Get("Float64Array").New(len(values))
+	js.CopyBytesToJS(bytesOf(array), raw)
+	return array
+}
+
+// newUint32ArrayBuffer returns a new JS ArrayBuffer holding values as
+// little-endian uint32s, 4 bytes each
+func newUint32ArrayBuffer(values []uint32) js.Value {
+	raw := make([]byte, len(values)*4)
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(raw[i*4:], value)
+	}
+
+	buffer := js.Global().Get("ArrayBuffer").New(len(raw))
+	js.CopyBytesToJS(js.Global().Get("Uint8Array").New(buffer), raw)
+	return buffer
+}
+
+// writeFloat64s copies values into the start of a JS Float64Array in one CopyBytesToJS call
+func writeFloat64s(array js.Value, values []float64) {
+	raw := make([]byte, len(values)*8)
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(raw[i*8:], math.Float64bits(value))
+	}
+	js.CopyBytesToJS(bytesOf(array).Call("subarray", 0, len(raw)), raw)
+}
+
+// writeFloat32s rounds values to float32 and copies them into the start of a
+// JS Float32Array in one CopyBytesToJS call
+func writeFloat32s(array js.Value, values []float64) {
+	raw := make([]byte, len(values)*4)
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(raw[i*4:], math.Float32bits(float32(value)))
+	}
+	js.CopyBytesToJS(bytesOf(array).Call("subarray", 0, len(raw)), raw)
+}
+
+// iterationArrayType returns the typed array constructor holding iteration
+// counts of bytesPerPixel bytes each
+func iterationArrayType(bytesPerPixel int) string {
+	switch bytesPerPixel {
+	case 1:
+		return "Uint8Array"
+	case 2:
+		return "Uint16Array"
+	default:
+		return "Uint32Array"
+	}
+}
+
+// packIterations encodes values into dst as little-endian unsigned integers
+// of bytesPerPixel (1, 2 or 4) bytes each, clamping values that don't fit to
+// the largest representable one
+func packIterations(dst []byte, values []uint32, bytesPerPixel int) {
+	switch bytesPerPixel {
+	case 1:
+		for i, value := range values {
+			dst[i] = uint8(min(value, math.MaxUint8))
+		}
+	case 2:
+		for i, value := range values {
+			binary.LittleEndian.PutUint16(dst[i*2:], uint16(min(value, math.MaxUint16)))
+		}
+	default:
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(dst[i*4:], value)
+		}
+	}
+}
+
+// writeIterations copies values into the start of a JS Uint8Array,
+// Uint16Array or Uint32Array with elements of bytesPerPixel bytes, clamped as
+// by packIterations, in one CopyBytesToJS call
+func writeIterations(array js.Value, values []uint32, bytesPerPixel int) {
+	raw := make([]byte, len(values)*bytesPerPixel)
+	packIterations(raw, values, bytesPerPixel)
+	js.CopyBytesToJS(bytesOf(array).Call("subarray", 0, len(raw)), raw)
+}
+
+// writeUint32s copies values into the start of a JS Uint32Array in one CopyBytesToJS call
+func writeUint32s(array js.Value, values []uint32) {
+	raw := make([]byte, len(values)*4)
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(raw[i*4:], value)
+	}
+	js.CopyBytesToJS(bytesOf(array).Call("subarray", 0, len(raw)), raw)
+}
@@ -0,0 +1,297 @@
+//go:build js
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"syscall/js"
+)
+
+// Argument validation
+//
+// Every exported function reads its arguments through an argReader. Invalid
+// arguments make the function return an object {error: "message"} naming the
+// function and the offending argument, instead of a bare 0, false or empty
+// array. Successful calls return the same values as before.
+
+// argReader reads and validates the arguments of an exported function,
+// recording the first problem it finds
+//
+// Accessors return zero values once an error has been recorded, so a
+// function can read all of its arguments and check failed once at the end.
+type argReader struct {
+	function string
+	args     []js.Value
+	err      string
+}
+
+// readArgs starts reading args for function, which accepts any of the given
+// argument counts
+func readArgs(function string, args []js.Value, counts ...int) *argReader {
+	r := &argReader{function: function, args: args}
+	for _, count := range counts {
+		if len(args) == count {
+			return r
+		}
+	}
+
+	expected := make([]string, len(counts))
+	for i, count := range counts {
+		expected[i] = fmt.Sprint(count)
+	}
+	list := expected[len(expected)-1]
+	if len(expected) > 1 {
+		list = strings.Join(expected[:len(expected)-1], ", ") + " or " + list
+	}
+	r.fail("expected %s arguments, got %d", list, len(args))
+	return r
+}
+
+// fail records a problem unless one was already recorded
+func (r *argReader) fail(format string, a ...interface{}) {
+	if r.err == "" {
+		r.err = r.function + ": " + fmt.Sprintf(format, a...)
+	}
+}
+
+// check records a problem when ok is false
+func (r *argReader) check(ok bool, format string, a ...interface{}) {
+	if !ok {
+		r.fail(format, a...)
+	}
+}
+
+// failed reports whether a problem has been recorded
+func (r *argReader) failed() bool {
+	return r.err != ""
+}
+
+// errorResult is the value returned to JavaScript for invalid arguments
+func (r *argReader) errorResult() interface{} {
+	return map[string]interface{}{"error": r.err}
+}
+
+// has reports whether the optional argument at index was passed
+func (r *argReader) has(index int) bool {
+	return index < len(r.args)
+}
+
+// value returns the raw argument at index
+func (r *argReader) value(index int) js.Value {
+	if r.failed() || !r.has(index) {
+		return js.Undefined()
+	}
+	return r.args[index]
+}
+
+// flag returns the truthiness of an optional argument, false when omitted
+func (r *argReader) flag(index int) bool {
+	return r.has(index) && r.value(index).Truthy()
+}
+
+// number returns a numeric argument
+func (r *argReader) number(index int, name string) float64 {
+	value := r.value(index)
+	if r.failed() {
+		return 0
+	}
+	if value.Type() != js.TypeNumber {
+		r.fail("%s must be a number, got %s", name, value.Type())
+		return 0
+	}
+	return value.Float()
+}
+
+// str returns a string argument
+func (r *argReader) str(index int, name string) string {
+	value := r.value(index)
+	if r.failed() {
+		return ""
+	}
+	if value.Type() != js.TypeString {
+		r.fail("%s must be a string, got %s", name, value.Type())
+		return ""
+	}
+	return value.String()
+}
+
+// integer returns a numeric argument truncated to an int
+func (r *argReader) integer(index int, name string) int {
+	value := r.number(index, name)
+	if r.failed() {
+		return 0
+	}
+	return int(value)
+}
+
+// positiveInteger returns a numeric argument of at least 1, truncated to an int
+func (r *argReader) positiveInteger(index int, name string) int {
+	value := r.number(index, name)
+	r.check(value >= 1, "%s must be a positive integer, got %v", name, value)
+	if r.failed() {
+		return 0
+	}
+	return int(value)
+}
+
+// maxIterations returns a maxIterations argument, which must be positive and
+// at most maxIterationsLimit
+func (r *argReader) maxIterations(index int) uint32 {
+	return r.iterationCount(index, "maxIterations")
+}
+
+// iterationCount returns an iteration count argument, which must be positive
+// and at most maxIterationsLimit, truncated to a uint32
+func (r *argReader) iterationCount(index int, name string) uint32 {
+	value := r.number(index, name)
+	r.check(value >= 1, "%s must be a positive integer, got %v", name, value)
+	r.check(value <= float64(maxIterationsLimit), "%s must be at most %d, got %v; see setMaxIterationsLimit", name, maxIterationsLimit, value)
+	if r.failed() {
+		return 0
+	}
+	return uint32(value)
+}
+
+// unboundedIterationCap is the iteration count used when maxIterations is 0
+// or negative, meaning "iterate until escape". Interior points never escape,
+// so they stop here; at 100000 iterations a single point takes about a
+// millisecond.
+const unboundedIterationCap = 100000
+
+// unboundedMaxIterations returns a maxIterations argument that may also be 0
+// or negative to request unboundedIterationCap, or maxIterationsLimit if lower
+func (r *argReader) unboundedMaxIterations(index int) uint32 {
+	if value := r.number(index, "maxIterations"); !r.failed() && value <= 0 {
+		return min(unboundedIterationCap, maxIterationsLimit)
+	}
+	return r.maxIterations(index)
+}
+
+// escapeRadius returns an escapeRadius argument, which must be positive
+func (r *argReader) escapeRadius(index int) float64 {
+	value := r.number(index, "escapeRadius")
+	r.check(value > 0, "escapeRadius must be greater than 0, got %v", value)
+	return value
+}
+
+// iterationSettings returns the maxIterations and escapeRadius arguments at
+// index and index+1, or the defaults from setDefaults when the call ends
+// before index
+func (r *argReader) iterationSettings(index int) (uint32, float64) {
+	if !r.has(index) {
+		return defaultMaxIterations, defaultEscapeRadius
+	}
+	return r.maxIterations(index), r.escapeRadius(index + 1)
+}
+
+// array returns a non-empty JS array or typed array argument
+func (r *argReader) array(index int, name string) js.Value {
+	value := r.value(index)
+	if r.failed() {
+		return value
+	}
+
+	isArray := js.Global().Get("Array").Call("isArray", value).Bool() ||
+		js.Global().Get("ArrayBuffer").Call("isView", value).Bool()
+	if !isArray {
+		r.fail("%s must be an array, got %s", name, value.Type())
+		return value
+	}
+	r.check(value.Length() > 0, "%s must not be empty", name)
+	return value
+}
+
+// typedArray returns an argument that must be an instance of the named
+// typed array constructor
+func (r *argReader) typedArray(index int, name, constructor string) js.Value {
+	value := r.value(index)
+	if r.failed() {
+		return value
+	}
+	r.check(isTypedArray(value, constructor), "%s must be a %s", name, constructor)
+	return value
+}
+
+// anyTypedArray returns an argument that must be a typed array of any
+// element type
+func (r *argReader) anyTypedArray(index int, name string) js.Value {
+	value := r.value(index)
+	if r.failed() {
+		return value
+	}
+	r.check(isArrayBufferView(value), "%s must be a typed array", name)
+	return value
+}
+
+// byteArray returns an argument that must be a Uint8Array or
+// Uint8ClampedArray
+func (r *argReader) byteArray(index int, name string) js.Value {
+	value := r.value(index)
+	if r.failed() {
+		return value
+	}
+	r.check(isByteArray(value), "%s must be a Uint8Array or Uint8ClampedArray", name)
+	return value
+}
+
+// minLength records a problem when buffer holds fewer than length elements
+func (r *argReader) minLength(buffer js.Value, name string, length int) {
+	if r.failed() {
+		return
+	}
+	r.check(buffer.Length() >= length, "%s must hold at least %d elements, got %d", name, length, buffer.Length())
+}
+
+// bytesPerPixel returns an optional iteration element size argument, which
+// must be 1, 2 or 4 and defaults to 4
+func (r *argReader) bytesPerPixel(index int) int {
+	if !r.has(index) {
+		return 4
+	}
+	value := r.integer(index, "bytesPerPixel")
+	r.check(value == 1 || value == 2 || value == 4, "bytesPerPixel must be 1, 2 or 4, got %d", value)
+	return value
+}
+
+// maxViewportPixels bounds the pixel count of every viewport argument at
+// 16384 x 16384, the largest canvas area browsers allow. Renderers allocate
+// per-pixel results in Go, so a larger count would overflow int or fail the
+// allocation and stop the runtime for every export.
+const maxViewportPixels = 1 << 28
+
+// dimensions returns width and height arguments, which must be positive
+// integers covering at most maxViewportPixels pixels
+func (r *argReader) dimensions(widthIndex, heightIndex int, widthName, heightName string) (int, int) {
+	width := r.number(widthIndex, widthName)
+	height := r.number(heightIndex, heightName)
+	r.check(width >= 1, "%s must be a positive integer, got %v", widthName, width)
+	r.check(height >= 1, "%s must be a positive integer, got %v", heightName, height)
+	if r.failed() {
+		return 0, 0
+	}
+	width, height = math.Trunc(width), math.Trunc(height)
+
+	// In float64, so the product cannot overflow
+	r.check(width*height <= maxViewportPixels, "%s*%s must be at most %d pixels, got %v x %v", widthName, heightName, maxViewportPixels, width, height)
+	if r.failed() {
+		return 0, 0
+	}
+	return int(width), int(height)
+}
+
+// viewport returns the (width, height, centerReal, centerImag, scale)
+// arguments starting at index
+func (r *argReader) viewport(index int) viewport {
+	width, height := r.dimensions(index, index+1, "width", "height")
+	view := viewport{
+		width:      width,
+		height:     height,
+		centerReal: r.number(index+2, "centerReal"),
+		centerImag: r.number(index+3, "centerImag"),
+		scaleX:     r.number(index+4, "scale"),
+	}
+	view.scaleY = view.scaleX
+	return view
+}
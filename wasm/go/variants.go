@@ -0,0 +1,193 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// Quadratic escape-time variants of the Mandelbrot set
+//
+// Each variant changes only how z is transformed before squaring; the
+// argument handling and escape test match calculatePoint and
+// calculateMandelbrotSet.
+
+// calculateBurningShipPoint calculates the number of iterations for a point in the
+// Burning Ship fractal, z = (|Re z| + |Im z|i)^2 + c
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The number of iterations before escape, or maxIterations if the point doesn't escape.
+//     After setStructuredResults(true), an object {escaped, iterations, smooth}.
+func calculateBurningShipPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateBurningShipPoint", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	iterations, zMagnitudeSquared := burningShipEscapeTime(real, imag, maxIterations, escapeRadiusSquared)
+	if structuredResults {
+		return pointResult(iterations, zMagnitudeSquared, escapeRadiusSquared, 2)
+	}
+	return iterations
+}
+
+// calculateBurningShipSet calculates the Burning Ship fractal for multiple points
+// in a single batch call
+//
+// Parameters:
+//   - realCoords: Array of real components for all points
+//   - imagCoords: Array of imaginary components for all points
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - Array of iteration counts, one for each input coordinate pair
+func calculateBurningShipSet(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateBurningShipSet", args, 4)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	return calculateBatch(realCoords, imagCoords, func(cReal, cImag float64) uint32 {
+		iterations, _ := burningShipEscapeTime(cReal, cImag, maxIterations, escapeRadiusSquared)
+		return iterations
+	})
+}
+
+// burningShipEscapeTime iterates the Burning Ship map starting from z = 0
+//
+// Returns the iteration count and |z|^2 at that point, as escapeTime does.
+func burningShipEscapeTime(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	zReal := 0.0
+	zImag := 0.0
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared
+		}
+
+		// Fold z into the first quadrant, then z = z^2 + c
+		zReal = math.Abs(zReal)
+		zImag = math.Abs(zImag)
+
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
+
+// calculateTricornPoint calculates the number of iterations for a point in the
+// Tricorn (Mandelbar) fractal, z = conj(z)^2 + c
+//
+// Parameters:
+//   - real: Real component of the complex number c
+//   - imag: Imaginary component of the complex number c
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - The number of iterations before escape, or maxIterations if the point doesn't escape.
+//     After setStructuredResults(true), an object {escaped, iterations, smooth}.
+func calculateTricornPoint(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateTricornPoint", args, 4)
+	real := r.number(0, "real")
+	imag := r.number(1, "imag")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	iterations, zMagnitudeSquared := tricornEscapeTime(real, imag, maxIterations, escapeRadiusSquared)
+	if structuredResults {
+		return pointResult(iterations, zMagnitudeSquared, escapeRadiusSquared, 2)
+	}
+	return iterations
+}
+
+// calculateTricornSet calculates the Tricorn fractal for multiple points in a
+// single batch call
+//
+// Parameters:
+//   - realCoords: Array of real components for all points
+//   - imagCoords: Array of imaginary components for all points
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - Array of iteration counts, one for each input coordinate pair
+func calculateTricornSet(this js.Value, args []js.Value) interface{} {
+	r := readArgs("calculateTricornSet", args, 4)
+	realCoords := r.array(0, "realCoords")
+	imagCoords := r.array(1, "imagCoords")
+	r.matchingLengths(realCoords, "realCoords", imagCoords, "imagCoords")
+	maxIterations := r.maxIterations(2)
+	escapeRadius := r.escapeRadius(3)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	escapeRadiusSquared := escapeThreshold(escapeRadius)
+
+	return calculateBatch(realCoords, imagCoords, func(cReal, cImag float64) uint32 {
+		iterations, _ := tricornEscapeTime(cReal, cImag, maxIterations, escapeRadiusSquared)
+		return iterations
+	})
+}
+
+// tricornEscapeTime iterates the Tricorn map starting from z = 0
+//
+// Returns the iteration count and |z|^2 at that point, as escapeTime does.
+func tricornEscapeTime(cReal, cImag float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	zReal := 0.0
+	zImag := 0.0
+
+	for iteration := uint32(0); iteration < maxIterations; iteration++ {
+		// Calculate |z|^2 = z_real^2 + z_imag^2
+		zMagnitudeSquared := zReal*zReal + zImag*zImag
+
+		// Check if point has escaped
+		if zMagnitudeSquared > escapeRadiusSquared {
+			return iteration, zMagnitudeSquared
+		}
+
+		// Conjugate z, then z = z^2 + c
+		zImag = -zImag
+
+		zRealTemp := zReal*zReal - zImag*zImag + cReal
+		zImag = 2.0*zReal*zImag + cImag
+		zReal = zRealTemp
+	}
+
+	// Point did not escape within maxIterations
+	return maxIterations, zReal*zReal + zImag*zImag
+}
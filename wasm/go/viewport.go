@@ -0,0 +1,441 @@
+//go:build js
+
+package main
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// viewport maps pixel coordinates on a width x height canvas to points on the
+// complex plane
+//
+// The canvas center maps to (centerReal, centerImag) and each pixel spans
+// scaleX complex units horizontally and scaleY vertically, which are equal
+// for square pixels. Following the canvas convention, x increases to the
+// right and y increases downward, so row 0 is the top edge and imaginary
+// values decrease from top to bottom.
+//
+// A viewport made with rotatedBy samples the plane rotated by rotation
+// radians about the center: each pixel's offset from the center is turned
+// counterclockwise before it is added, so the image shows the set turned
+// clockwise by the same angle.
+type viewport struct {
+	width      int
+	height     int
+	centerReal float64
+	centerImag float64
+	scaleX     float64
+	scaleY     float64
+	rotation   float64
+	cosRot     float64
+	sinRot     float64
+}
+
+// rotatedBy returns the viewport rotated by angle radians about its center
+func (v viewport) rotatedBy(angle float64) viewport {
+	v.rotation = angle
+	v.sinRot, v.cosRot = math.Sincos(angle)
+	return v
+}
+
+// offsetAt returns the complex offset from the center of a sample displaced
+// from pixel (x, y) by (dx, dy) pixels, rotated when the viewport is
+func (v viewport) offsetAt(x, y int, dx, dy float64) (float64, float64) {
+	offsetReal := (float64(x) + dx - float64(v.width)/2) * v.scaleX
+	offsetImag := -(float64(y) + dy - float64(v.height)/2) * v.scaleY
+	if v.rotation == 0 {
+		return offsetReal, offsetImag
+	}
+	return v.cosRot*offsetReal - v.sinRot*offsetImag, v.sinRot*offsetReal + v.cosRot*offsetImag
+}
+
+// pixelCount returns the number of pixels covered by the viewport
+func (v viewport) pixelCount() int {
+	if v.width <= 0 || v.height <= 0 {
+		return 0
+	}
+	return v.width * v.height
+}
+
+// pointAt returns the complex coordinate of pixel (x, y)
+func (v viewport) pointAt(x, y int) (float64, float64) {
+	return v.pointAtOffset(x, y, 0, 0)
+}
+
+// pixelAt returns the pixel whose sample point is nearest to the complex
+// coordinate (real, imag), and whether that pixel lies inside the viewport.
+// It is the inverse of pointAt.
+func (v viewport) pixelAt(real, imag float64) (int, int, bool) {
+	offsetReal, offsetImag := real-v.centerReal, imag-v.centerImag
+	if v.rotation != 0 {
+		offsetReal, offsetImag = v.cosRot*offsetReal+v.sinRot*offsetImag, -v.sinRot*offsetReal+v.cosRot*offsetImag
+	}
+	x := math.Floor(offsetReal/v.scaleX + float64(v.width)/2 + 0.5)
+	y := math.Floor(-offsetImag/v.scaleY + float64(v.height)/2 + 0.5)
+	if !(x >= 0 && x < float64(v.width) && y >= 0 && y < float64(v.height)) {
+		return 0, 0, false
+	}
+	return int(x), int(y), true
+}
+
+// pointAtOffset returns the complex coordinate of a sample displaced from
+// pixel (x, y) by (dx, dy) pixels, for subpixel sampling
+func (v viewport) pointAtOffset(x, y int, dx, dy float64) (float64, float64) {
+	offsetReal, offsetImag := v.offsetAt(x, y, dx, dy)
+	return v.centerReal + offsetReal, v.centerImag + offsetImag
+}
+
+// pointAtOffsetDD is pointAtOffset in double-double precision
+//
+// At deep zoom the offset from the center is many orders of magnitude
+// smaller than the center itself, so summing in float64 would round away the
+// bits that distinguish neighbouring pixels. The offset alone is exact enough
+// in float64; only the sum needs the extra precision.
+func (v viewport) pointAtOffsetDD(x, y int, dx, dy float64) (doubleDouble, doubleDouble) {
+	offsetReal, offsetImag := v.offsetAt(x, y, dx, dy)
+	return ddFromSum(v.centerReal, offsetReal), ddFromSum(v.centerImag, offsetImag)
+}
+
+// escapeTimeAt returns the Mandelbrot iteration count and final |z|^2 of a
+// sample displaced from pixel (x, y) by (dx, dy) pixels, using Q4.60 fixed
+// point when fixedPoint is enabled, or else double-double arithmetic when
+// highPrecision is enabled
+//
+// Points in the main cardioid or period-2 bulb return maxIterations without
+// iterating; their magnitude is reported as 0.
+func (v viewport) escapeTimeAt(x, y int, dx, dy float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64) {
+	iterations, zMagnitudeSquared, _ := v.escapeTimeAtProven(x, y, dx, dy, maxIterations, escapeRadiusSquared)
+	return iterations, zMagnitudeSquared
+}
+
+// escapeTimeAtProven is escapeTimeAt also reporting whether the sample was
+// proven interior, by the cardioid and bulb test or periodicity checking
+func (v viewport) escapeTimeAtProven(x, y int, dx, dy float64, maxIterations uint32, escapeRadiusSquared float64) (uint32, float64, bool) {
+	if fixedPoint {
+		cReal, cImag := v.pointAtOffset(x, y, dx, dy)
+		if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag) {
+			return maxIterations, 0, true
+		}
+		iterations, zMagnitudeSquared := escapeTimeFixed(cReal, cImag, maxIterations, escapeRadiusSquared)
+		return iterations, zMagnitudeSquared, false
+	}
+	if highPrecision {
+		cReal, cImag := v.pointAtOffsetDD(x, y, dx, dy)
+		if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal.hi, cImag.hi) {
+			return maxIterations, 0, true
+		}
+		iterations, zMagnitudeSquared := escapeTimeDD(cReal, cImag, maxIterations, escapeRadiusSquared)
+		return iterations, zMagnitudeSquared, false
+	}
+
+	cReal, cImag := v.pointAtOffset(x, y, dx, dy)
+	if escapeRadiusSquared >= interiorShortcutRadiusSquared && inCardioidOrBulb(cReal, cImag) {
+		return maxIterations, 0, true
+	}
+	return escapeTimeProven(0, 0, cReal, cImag, maxIterations, escapeRadiusSquared)
+}
+
+// countAt is the iteration count of escapeTimeAt as the count-only renderers
+// report it, with proven interior samples reported as the interior value
+// when one is set
+func (v viewport) countAt(x, y int, dx, dy float64, maxIterations uint32, escapeRadiusSquared float64) uint32 {
+	iterations, _, proven := v.escapeTimeAtProven(x, y, dx, dy, maxIterations, escapeRadiusSquared)
+	return reportedCount(iterations, proven)
+}
+
+// escapeTimes computes the Mandelbrot iteration count of every pixel in
+// row-major order (index = y*width + x), or column-major order
+// (index = x*height + y) when columnMajor is set
+//
+// Returns the results and the number of leading pixels that were completed,
+// which is less than pixelCount only when the render was cancelled.
+func (v viewport) escapeTimes(columnMajor bool, maxIterations uint32, escapeRadiusSquared float64) ([]uint32, int) {
+	results := make([]uint32, v.pixelCount())
+	completed := v.fillEscapeTimes(results, columnMajor, maxIterations, escapeRadiusSquared)
+	return results, completed
+}
+
+// fillEscapeTimes is escapeTimes writing into a caller-provided slice of at
+// least pixelCount elements, using neighbor guessing for row-major renders
+// when it is enabled, or sampling at jittered positions when that is
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillEscapeTimes(results []uint32, columnMajor bool, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+	if neighborGuessing && !columnMajor && !sampleJitter {
+		return v.fillGuessed(results, maxIterations, escapeRadiusSquared)
+	}
+
+	// Lines (rows, or columns in column-major order) are split across
+	// workers, so completed lines always form a prefix of the output
+	lines, lineLength := v.height, v.width
+	if columnMajor {
+		lines, lineLength = v.width, v.height
+	}
+
+	// Jitter offsets are indexed like results
+	var offsets []sampleOffset
+	if sampleJitter {
+		offsets = jitterOffsets(v.pixelCount())
+	}
+
+	renderProgress.begin(lines)
+	completedLines := parallelFor(lines, func(startLine, endLine int) int {
+		for line := startLine; line < endLine; line++ {
+			if (line-startLine)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return line - startLine
+			}
+
+			for i := 0; i < lineLength; i++ {
+				x, y := i, line
+				if columnMajor {
+					x, y = line, i
+				}
+				var offset sampleOffset
+				if offsets != nil {
+					offset = offsets[line*lineLength+i]
+				}
+				results[line*lineLength+i] = v.countAt(x, y, offset.dx, offset.dy, maxIterations, escapeRadiusSquared)
+			}
+			renderProgress.rowDone()
+		}
+		return endLine - startLine
+	})
+
+	return completedLines * lineLength
+}
+
+// fillSymmetric is fillEscapeTimes for a viewport centered on the real axis,
+// computing the rows on and above the axis and mirroring them into the rows
+// below
+//
+// With centerImag 0, row height-y samples exactly the conjugate of row y,
+// and every arithmetic mode iterates conjugate points to the same count, so
+// the mirrored rows are bit-for-bit what computing them would give. Row 0
+// has no partner inside the viewport. Neighbor guessing is not used.
+//
+// Returns the number of leading pixels that were completed.
+func (v viewport) fillSymmetric(results []uint32, columnMajor bool, maxIterations uint32, escapeRadiusSquared float64) int {
+	if v.pixelCount() == 0 {
+		return 0
+	}
+
+	index := func(x, y int) int {
+		if columnMajor {
+			return x*v.height + y
+		}
+		return y*v.width + x
+	}
+
+	computedRows := v.height/2 + 1
+	renderProgress.begin(computedRows)
+	completedRows := parallelFor(computedRows, func(startRow, endRow int) int {
+		for y := startRow; y < endRow; y++ {
+			if (y-startRow)%rowsPerCancelCheck == 0 && isRenderCancelled() {
+				return y - startRow
+			}
+
+			for x := 0; x < v.width; x++ {
+				results[index(x, y)] = v.countAt(x, y, 0, 0, maxIterations, escapeRadiusSquared)
+			}
+			renderProgress.rowDone()
+		}
+		return endRow - startRow
+	})
+
+	if completedRows < computedRows {
+		if columnMajor {
+			return 0
+		}
+		return completedRows * v.width
+	}
+
+	for y := computedRows; y < v.height; y++ {
+		for x := 0; x < v.width; x++ {
+			results[index(x, y)] = results[index(x, v.height-y)]
+		}
+	}
+	return v.pixelCount()
+}
+
+// renderViewport calculates the Mandelbrot set for every pixel of a viewport
+// in a single call, generating the coordinates on the Go side
+//
+// Parameters:
+//   - width: Viewport width in pixels
+//   - height: Viewport height in pixels
+//   - centerReal: Real component at the center of the viewport
+//   - centerImag: Imaginary component at the center of the viewport
+//   - scale: Complex-plane units per pixel along the real axis
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//   - resultBuf: Typed array of at least width*height elements receiving the
+//     iteration counts
+//   - columnMajor (optional): When true, pixel (x, y) is stored at index
+//     x*height + y, for direct upload as a column-major texture. By default
+//     the order is row-major, index y*width + x.
+//   - bytesPerPixel (optional): 1, 2 or 4 (the default), writing into a
+//     Uint8Array, Uint16Array or Uint32Array resultBuf respectively. Counts
+//     too large for the element type are clamped to its maximum.
+//   - aspect (optional): Pixel aspect ratio, the imaginary units per pixel
+//     divided by the real units per pixel (default 1). Rows then span
+//     scale*aspect imaginary units; the center stays at pixel
+//     (width/2, height/2) whatever the aspect.
+//   - symmetric (optional): When true and centerImag is 0, only the rows on
+//     and above the real axis are computed and the rows below are mirrored
+//     from them, with identical results. Ignored for other views, including
+//     rotated ones, and while setJitter is enabled.
+//   - onProgress (optional): Function called with the fraction of rows done,
+//     in (0, 1], every progressRows rows and once the last row is done. It
+//     runs synchronously inside this call; see progress.go.
+//   - progressRows (optional): Rows between progress reports, at least 1
+//     (default 16)
+//   - rotation (optional): Angle in radians to rotate the view by about its
+//     center (default 0). Pixel offsets from the center are rotated
+//     counterclockwise on the complex plane, turning the image clockwise.
+//     Pass null for onProgress to rotate without progress reports.
+//
+// The scale is clamped to the range set by setBounds, if any.
+//
+// Returns:
+//   - The number of pixels written, or {error} if the arguments or buffer are invalid.
+//     If cancelRender stops the render, an object {written, cancelled: true}
+//     where written counts the leading elements that were filled. If the
+//     scale was clamped, an object {written, clamped: true, scale} with the
+//     scale used, which also carries cancelled: true for a cancelled render.
+func renderViewport(this js.Value, args []js.Value) interface{} {
+	r := readArgs("renderViewport", args, 8, 9, 10, 11, 12, 13, 14, 15)
+	view := r.viewport(0)
+	if r.has(10) {
+		aspect := r.number(10, "aspect")
+		r.check(aspect > 0, "aspect must be greater than 0, got %v", aspect)
+		view.scaleY = view.scaleX * aspect
+	}
+	if r.has(14) {
+		view = view.rotatedBy(r.number(14, "rotation"))
+	}
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	columnMajor := r.flag(8)
+	bytesPerPixel := r.bytesPerPixel(9)
+	symmetric := r.flag(11) && view.centerImag == 0 && view.rotation == 0 && !sampleJitter
+	progress := r.progress(12)
+	resultBuf := r.typedArray(7, "resultBuf", iterationArrayType(bytesPerPixel))
+	r.minLength(resultBuf, "resultBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+	view, clamped := view.clampScale()
+
+	beginRender()
+	renderProgress = progress
+	defer func() { renderProgress = nil }()
+
+	results := make([]uint32, view.pixelCount())
+	var completed int
+	if symmetric {
+		completed = view.fillSymmetric(results, columnMajor, maxIterations, escapeThreshold(escapeRadius))
+	} else {
+		completed = view.fillEscapeTimes(results, columnMajor, maxIterations, escapeThreshold(escapeRadius))
+	}
+
+	writeIterations(resultBuf, results[:completed], bytesPerPixel)
+	if clamped {
+		result := map[string]interface{}{
+			"written": completed,
+			"clamped": true,
+			"scale":   view.scaleX,
+		}
+		if completed < len(results) {
+			result["cancelled"] = true
+		}
+		return result
+	}
+	if completed < len(results) {
+		return cancelledResult(completed)
+	}
+	return completed
+}
+
+// generateCoordinates fills two buffers with the complex coordinate of every
+// pixel of a viewport, for callers that feed them to the batch functions
+//
+// Each coordinate is computed from the pixel index as center + offset*scale,
+// exactly as renderViewport samples it, rather than by adding scale once per
+// pixel, so rounding errors don't accumulate across the row and neighbouring
+// tiles line up without seams.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - realBuf: Float64Array of at least width*height elements receiving the
+//     real components in row-major order
+//   - imagBuf: Float64Array of at least width*height elements receiving the
+//     imaginary components in row-major order
+//
+// Returns:
+//   - The number of coordinate pairs written, or {error} if the arguments or
+//     buffers are invalid
+func generateCoordinates(this js.Value, args []js.Value) interface{} {
+	r := readArgs("generateCoordinates", args, 7)
+	view := r.viewport(0)
+	realBuf := r.typedArray(5, "realBuf", "Float64Array")
+	imagBuf := r.typedArray(6, "imagBuf", "Float64Array")
+	r.minLength(realBuf, "realBuf", view.pixelCount())
+	r.minLength(imagBuf, "imagBuf", view.pixelCount())
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	realCoords := make([]float64, view.pixelCount())
+	imagCoords := make([]float64, view.pixelCount())
+	for y := 0; y < view.height; y++ {
+		for x := 0; x < view.width; x++ {
+			index := y*view.width + x
+			realCoords[index], imagCoords[index] = view.pointAt(x, y)
+		}
+	}
+
+	writeFloat64s(realBuf, realCoords)
+	writeFloat64s(imagBuf, imagCoords)
+	return view.pixelCount()
+}
+
+// computeGrid calculates the Mandelbrot set for every pixel of a viewport like
+// renderViewport, returning the counts in a new Uint32Array
+//
+// Coordinates are generated and iterated in one pass on the Go side, so
+// neither coordinate arrays nor a result buffer need to be allocated in JS
+// beforehand. Samples follow the same settings as renderViewport, including
+// neighbor guessing and jitter.
+//
+// Parameters:
+//   - width, height, centerReal, centerImag, scale: Viewport, as for renderViewport
+//   - maxIterations: Maximum number of iterations to perform
+//   - escapeRadius: Threshold beyond which a point is considered escaped
+//
+// Returns:
+//   - A Uint32Array of width*height iteration counts in row-major order. If
+//     cancelRender stops the render it holds only the leading pixels
+//     completed so far and has a cancelled property set to true. {error} for
+//     invalid arguments.
+func computeGrid(this js.Value, args []js.Value) interface{} {
+	r := readArgs("computeGrid", args, 7)
+	view := r.viewport(0)
+	maxIterations := r.maxIterations(5)
+	escapeRadius := r.escapeRadius(6)
+	if r.failed() {
+		return r.errorResult()
+	}
+
+	beginRender()
+	results, completed := view.escapeTimes(false, maxIterations, escapeThreshold(escapeRadius))
+
+	array := js.Global().Get("Uint32Array").New(newUint32ArrayBuffer(results[:completed]))
+	if completed < len(results) {
+		array.Set("cancelled", true)
+	}
+	return array
+}